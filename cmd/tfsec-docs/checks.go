@@ -17,9 +17,9 @@ has_toc: false
 
 The included {{$.Provider | ToUpper}} checks are listed below. For more information about each check, see the link provided.
 
-| ID  | Summary |
-|:-------|:-------------|
-{{range $check := .Checks}}|[{{$check.ID}}](/docs/{{$.Provider}}/{{$check.ID}})|{{$check.Documentation.Summary}}|
+| ID  | Severity | Summary |
+|:-------|:-------|:-------------|
+{{range $check := .Checks}}|[{{$check.ID}}](/docs/{{$.Provider}}/{{$check.ID}})|{{$check.DefaultSeverity}}|{{$check.Documentation.Summary}}|
 {{end}}
 `
 
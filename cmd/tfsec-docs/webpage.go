@@ -15,6 +15,8 @@ const (
 title: {{$.ID}} - {{$.Documentation.Summary}}
 summary: {{$.Documentation.Summary}} 
 resources: {{$.RequiredLabels}} 
+severity: {{$.DefaultSeverity}}
+tags: {{$.Tags}}
 permalink: /docs/{{$.Provider}}/{{$.ID}}/
 ---
 ### Explanation
@@ -41,6 +43,14 @@ The following example will pass the {{$.ID}} check.
 {% endhighlight %}
 {{end}}
 
+{{if $.Compliance}}
+### Compliance
+
+{{range $control := $.Compliance}}
+- {{$control.Framework.Name}}: {{$control.ID}}
+{{end}}
+{{end}}
+
 {{if $.Documentation.Links}}
 ### Related Links
 
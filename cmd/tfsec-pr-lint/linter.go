@@ -39,6 +39,12 @@ func (l *linter) lint(check rule.Rule) {
 		errorFound = true
 	}
 
+	if !check.DefaultSeverity.IsValid() {
+		fmt.Printf("%s: Has no valid default severity\n", check.ID)
+		l.exitCode = 1
+		errorFound = true
+	}
+
 	if len(docs.Links) == 0 {
 		fmt.Printf("%s: Has no links configure\n", check.ID)
 		errorFound = true
@@ -9,6 +9,7 @@ import (
 	"github.com/tfsec/tfsec/pkg/provider"
 	"github.com/tfsec/tfsec/pkg/result"
 	"github.com/tfsec/tfsec/pkg/rule"
+	"github.com/tfsec/tfsec/pkg/severity"
 )
 
 
@@ -40,9 +41,11 @@ func init() {
 				
 			},
 		},
-		Provider:       provider.{{.ProviderLongName}}Provider,
-		RequiredTypes:  []string{{.RequiredTypes}},
-		RequiredLabels: []string{{.RequiredLabels}},
+		Provider:        provider.{{.ProviderLongName}}Provider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{},
+		RequiredTypes:   []string{{.RequiredTypes}},
+		RequiredLabels:  []string{{.RequiredLabels}},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context){
 				
 			// function contents here
@@ -7,10 +7,14 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
+	"github.com/tfsec/tfsec/pkg/rule"
 
 	"github.com/tfsec/tfsec/pkg/severity"
 
+	"github.com/tfsec/tfsec/internal/app/tfsec/baseline"
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
 	"github.com/tfsec/tfsec/internal/app/tfsec/config"
 	"github.com/tfsec/tfsec/internal/app/tfsec/updater"
 
@@ -19,6 +23,7 @@ import (
 	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/formatters"
+	"github.com/tfsec/tfsec/internal/app/tfsec/gitdiff"
 
 	"github.com/liamg/tml"
 
@@ -37,7 +42,8 @@ var format string
 var softFail = false
 var filterResults string
 var excludedRuleIDs string
-var tfvarsPath string
+var tfvarsFiles = &tfvarsFileFlag{}
+var vars = &varFlag{}
 var outputFlag string
 var customCheckDir string
 var configFile string
@@ -52,6 +58,20 @@ var ignoreInfo = false
 var allDirs = false
 var runStatistics bool
 var ignoreHCLErrors bool
+var moduleCacheDir string
+var workspace string
+var planFile string
+var stateFile string
+var parallelism = 1
+var failOnDiagnostics bool
+var rootModules bool
+var reportUnresolved bool
+var reportUnusedIgnores bool
+var baselineFile string
+var writeBaselineFile string
+var sinceRef string
+var includeTags string
+var compliance string
 
 func init() {
 	rootCmd.Flags().BoolVar(&ignoreHCLErrors, "ignore-hcl-errors", ignoreHCLErrors, "Stop and report an error if an HCL parse error is encountered")
@@ -61,9 +81,12 @@ func init() {
 	rootCmd.Flags().BoolVar(&runUpdate, "update", runUpdate, "Update to latest version")
 	rootCmd.Flags().StringVarP(&format, "format", "f", format, "Select output format: default, json, csv, checkstyle, junit, sarif")
 	rootCmd.Flags().StringVarP(&excludedRuleIDs, "exclude", "e", excludedRuleIDs, "Provide comma-separated list of rule IDs to exclude from run.")
+	rootCmd.Flags().StringVar(&includeTags, "include-tags", includeTags, "Only run checks with at least one of these tags (supports comma-delimited input): encryption, logging, network, iam, backup, hardening")
+	rootCmd.Flags().StringVar(&compliance, "compliance", compliance, "Only run checks mapped to a compliance framework: cis-aws-1.4, cis-azure-1.3, cis-gcp-1.2, pci-dss-3.2.1")
 	rootCmd.Flags().StringVar(&filterResults, "filter-results", filterResults, "Filter results to return specific checks only (supports comma-delimited input).")
 	rootCmd.Flags().BoolVarP(&softFail, "soft-fail", "s", softFail, "Runs checks but suppresses error code")
-	rootCmd.Flags().StringVar(&tfvarsPath, "tfvars-file", tfvarsPath, "Path to .tfvars file")
+	rootCmd.Flags().Var(tfvarsFiles, "tfvars-file", "Path to .tfvars file, can be specified multiple times")
+	rootCmd.Flags().Var(vars, "var", "Set a variable in the form name=value, can be specified multiple times")
 	rootCmd.Flags().StringVar(&outputFlag, "out", outputFlag, "Set output file")
 	rootCmd.Flags().StringVar(&customCheckDir, "custom-check-dir", customCheckDir, "Explicitly the custom checks dir location")
 	rootCmd.Flags().StringVar(&configFile, "config-file", configFile, "Config file to use during run")
@@ -73,10 +96,22 @@ func init() {
 	rootCmd.Flags().BoolVar(&detailedExitCode, "detailed-exit-code", detailedExitCode, "Produce more detailed exit status codes.")
 	rootCmd.Flags().BoolVar(&includePassed, "include-passed", includePassed, "Include passed checks in the result output")
 	rootCmd.Flags().BoolVar(&includeIgnored, "include-ignored", includeIgnored, "Include ignored checks in the result output")
+	rootCmd.Flags().BoolVar(&reportUnresolved, "report-unresolved", reportUnresolved, "Include checks which could not be made because a value was not known, such as a value read from a data source or an unset variable")
+	rootCmd.Flags().BoolVar(&reportUnusedIgnores, "report-unused-ignores", reportUnusedIgnores, "List tfsec:ignore comments which did not match any result or which name a rule that does not exist, and fail if there are any")
+	rootCmd.Flags().StringVar(&baselineFile, "baseline", baselineFile, "Baseline file of accepted findings, which are marked as baselined so that only new findings fail the run")
+	rootCmd.Flags().StringVar(&writeBaselineFile, "write-baseline", writeBaselineFile, "Write the current findings to a baseline file, accepting them in future runs which use --baseline")
+	rootCmd.Flags().StringVar(&sinceRef, "since", sinceRef, "Only report results for files changed since this git ref, such as origin/main, and for modules called from them")
 	rootCmd.Flags().BoolVar(&allDirs, "force-all-dirs", allDirs, "Don't search for tf files, include everything below provided directory.")
+	rootCmd.Flags().BoolVar(&rootModules, "root-modules", rootModules, "Find every root module below the provided directory and scan each of them separately")
 	rootCmd.Flags().BoolVar(&runStatistics, "run-statistics", runStatistics, "View statistics table of current findings.")
 	rootCmd.Flags().BoolVar(&ignoreWarnings, "ignore-warnings", ignoreWarnings, "Don't show warnings in the output.")
 	rootCmd.Flags().BoolVar(&ignoreInfo, "ignore-info", ignoreWarnings, "Don't show info results in the output.")
+	rootCmd.Flags().StringVar(&planFile, "plan", planFile, "Scan a plan in JSON format (created with 'terraform show -json') instead of the source code")
+	rootCmd.Flags().StringVar(&stateFile, "state", stateFile, "Scan the resources recorded in a terraform state file (version 4) instead of the source code")
+	rootCmd.Flags().StringVar(&workspace, "workspace", workspace, "Terraform workspace to evaluate the configuration for (default \"default\")")
+	rootCmd.Flags().BoolVar(&failOnDiagnostics, "fail-on-diagnostics", failOnDiagnostics, "Exit with a failure if any problems were found while reading the configuration, such as modules which could not be loaded")
+	rootCmd.Flags().IntVar(&parallelism, "parallelism", parallelism, "Number of files and modules to read and parse at the same time")
+	rootCmd.Flags().StringVar(&moduleCacheDir, "module-cache-dir", moduleCacheDir, "Resolve registry, git and archive modules from this local cache instead of requiring 'terraform init'")
 }
 
 func main() {
@@ -163,6 +198,7 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		debug.Log("Custom checks loaded")
+		checkSeverityOverrides()
 
 		if len(filterResults) > 0 {
 			filterResultsList = strings.Split(filterResults, ",")
@@ -186,19 +222,60 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if tfvarsPath == "" && unusedTfvarsPresent(dir) {
+		if planFile != "" && stateFile != "" {
+			return fmt.Errorf("--plan and --state cannot be used together")
+		}
+		if baselineFile != "" && writeBaselineFile != "" {
+			return fmt.Errorf("--baseline and --write-baseline cannot be used together")
+		}
+		if _, err := getIncludedTags(); err != nil {
+			return err
+		}
+		if compliance != "" && !rule.Framework(compliance).IsValid() {
+			return fmt.Errorf("unknown compliance framework '%s', must be one of %v", compliance, rule.ValidFrameworks)
+		}
+		if sinceRef != "" && (planFile != "" || stateFile != "") {
+			return fmt.Errorf("--since cannot be used with --plan or --state")
+		}
+		if rootModules && (planFile != "" || stateFile != "" || allDirs) {
+			return fmt.Errorf("--root-modules cannot be used with --plan, --state or --force-all-dirs")
+		}
+		if !rootModules && planFile == "" && stateFile == "" && !allDirs && parser.IsTerragruntRepository(dir) {
+			// each terragrunt unit deploys its configuration with its own inputs, which is only done with --root-modules
+			_, _ = fmt.Fprint(os.Stderr, tml.Sprintf("\n<yellow>Warning: This directory contains terragrunt units, which are not scanned with their inputs. \nUse the --root-modules flag to scan each unit separately.</yellow>\n"))
+		}
+
+		if planFile == "" && stateFile == "" && len(tfvarsFiles.paths) == 0 && unusedTfvarsPresent(dir) {
 			_ = tml.Printf("\n<yellow>Warning: A tfvars file was found but not automatically used. \nDid you mean to specify the --tfvars-file flag?</yellow>\n")
 		}
 
-		debug.Log("Starting parser...")
-		blocks, err := parser.New(dir, getParserOptions()...).ParseDirectory()
+		var results []result.Result
+		var diagnostics diagnostic.Diagnostics
+		scannerOptions := getScannerOptions()
+		if sinceRef != "" {
+			changedFiles, err := gitdiff.ChangedFiles(dir, sinceRef)
+			if err != nil {
+				return err
+			}
+			scannerOptions = append(scannerOptions, scanner.OptionReportOnlyFiles(changedFiles))
+		}
+		projectScanner := scanner.New(scannerOptions...)
+		if rootModules {
+			results, diagnostics, err = scanRootModules(dir, projectScanner)
+		} else {
+			results, diagnostics, err = scanProject(dir, projectScanner)
+		}
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+		diagnostics = append(diagnostics, projectScanner.Diagnostics()...)
+		if !formatReportsDiagnostics() {
+			for _, diag := range diagnostics {
+				_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", diag.Severity, diag)
+			}
+		}
 
-		debug.Log("Starting scanner...")
-		results := scanner.New(getScannerOptions()...).Scan(blocks)
 		results = updateResultSeverity(results)
 		results = RemoveDuplicatesAndUnwanted(results, ignoreWarnings, excludeDownloaded)
 		if len(filterResultsList) > 0 {
@@ -213,6 +290,11 @@ var rootCmd = &cobra.Command{
 			results = filteredResult
 		}
 
+		results, err = applyBaseline(results)
+		if err != nil {
+			return err
+		}
+
 		if runStatistics {
 			statistics := scanner.Statistics{}
 			for _, result := range results {
@@ -222,10 +304,20 @@ var rootCmd = &cobra.Command{
 			return nil
 		}
 
-		if err := formatter(outputFile, results, dir, getFormatterOptions()...); err != nil {
+		if err := formatter(outputFile, results, diagnostics, dir, getFormatterOptions()...); err != nil {
 			return err
 		}
 
+		// ignores which no longer suppress anything fail the run when they are reported, so that they are removed
+		if reportUnusedIgnores && printUnusedIgnores(projectScanner.UnusedIgnores()) > 0 && !softFail {
+			os.Exit(1)
+		}
+
+		// diagnostics mean part of the configuration may not have been scanned, which fails the run if requested
+		if failOnDiagnostics && len(diagnostics) > 0 && !softFail {
+			os.Exit(1)
+		}
+
 		// Soft fail always takes precedence. If set, only execution errors
 		// produce a failure exit code (1).
 		if softFail {
@@ -247,28 +339,106 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// scanProject parses and scans the directory as a single project, or the plan or state file if one was given
+func scanProject(dir string, projectScanner *scanner.Scanner) ([]result.Result, diagnostic.Diagnostics, error) {
+
+	debug.Log("Starting parser...")
+	var blocks block.Blocks
+	var err error
+	tfParser := parser.New(dir, getParserOptions()...)
+	switch {
+	case planFile != "":
+		blocks, err = tfParser.ParsePlan(planFile)
+	case stateFile != "":
+		blocks, err = parser.LoadState(stateFile)
+	default:
+		blocks, err = tfParser.ParseDirectory()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	debug.Log("Starting scanner...")
+	return projectScanner.Scan(blocks), tfParser.Diagnostics(), nil
+}
+
+// scanRootModules scans every root module below the directory as a project of its own, with its own variables and
+// modules, and tags each result with the root module it was found in. The same scanner is used for every root module, so
+// that it knows which ignores were used in any of them.
+func scanRootModules(dir string, projectScanner *scanner.Scanner) ([]result.Result, diagnostic.Diagnostics, error) {
+
+	debug.Log("Finding root modules...")
+	roots, err := parser.New(dir, getParserOptions()...).FindRootModules()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []result.Result
+	var diagnostics diagnostic.Diagnostics
+	for _, root := range roots {
+		name, err := filepath.Rel(dir, root)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		debug.Log("Starting parser for root module '%s'...", name)
+		rootParser := parser.New(root, getParserOptions()...)
+		blocks, err := rootParser.ParseDirectory()
+		if err != nil {
+			return nil, nil, err
+		}
+		diagnostics = append(diagnostics, rootParser.Diagnostics()...)
+
+		debug.Log("Starting scanner for root module '%s'...", name)
+		for _, res := range projectScanner.Scan(blocks) {
+			res.WithRoot(filepath.ToSlash(name))
+			results = append(results, res)
+		}
+	}
+	return results, diagnostics, nil
+}
+
+// printUnusedIgnores lists the ignore comments which did not match any result, and returns how many there were. Ignores
+// in downloaded modules are left out when results for downloaded modules are.
+func printUnusedIgnores(unusedIgnores []scanner.UnusedIgnore) int {
+	count := 0
+	for _, unused := range unusedIgnores {
+		if excludeDownloaded && strings.Contains(unused.Range.Filename, fmt.Sprintf("%c.terraform", os.PathSeparator)) {
+			continue
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Unused ignore: %s\n", unused)
+		count++
+	}
+	return count
+}
+
 func getParserOptions() []parser.Option {
 	var opts []parser.Option
 	if allDirs {
 		opts = append(opts, parser.OptionDoNotSearchTfFiles())
 	}
-	if tfvarsPath != "" {
-		tfvarsPath, err := filepath.Abs(tfvarsPath)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		opts = append(opts, parser.OptionWithTFVarsPath(tfvarsPath))
+	if len(varsSources) > 0 {
+		opts = append(opts, parser.OptionWithVarsSources(varsSources...))
 	}
 	if !ignoreHCLErrors {
 		opts = append(opts, parser.OptionStopOnHCLError())
 	}
+	if moduleCacheDir != "" {
+		opts = append(opts, parser.OptionWithModuleResolver(parser.ModuleCache(moduleCacheDir)))
+	}
+	if workspace != "" {
+		opts = append(opts, parser.OptionWithWorkspaceName(workspace))
+	} else if tfsecConfig.Workspace != "" {
+		opts = append(opts, parser.OptionWithWorkspaceName(tfsecConfig.Workspace))
+	}
+	opts = append(opts, parser.OptionWithParallelism(parallelism))
 	return opts
 }
 
 func getDetailedExitCode(results []result.Result) int {
-	// If there are no failed rules, then produce a success exit code (0).
-	if len(results) == 0 || len(results) == countPassedResults(results) {
+	// If there are no failed rules, then produce a success exit code (0). Ignored and baselined checks, and checks which
+	// could not be made, are not failures.
+	if len(results) == 0 || len(results) == countPassedResults(results)+countUnresolvedResults(results)+countIgnoredResults(results)+countBaselinedResults(results) {
 		return 0
 	}
 
@@ -328,6 +498,9 @@ func getScannerOptions() []scanner.Option {
 	if includeIgnored {
 		options = append(options, scanner.OptionIncludeIgnored())
 	}
+	if reportUnresolved {
+		options = append(options, scanner.OptionIncludeUnresolved())
+	}
 
 	var allExcludedRuleIDs []string
 	for _, exclude := range strings.Split(excludedRuleIDs, ",") {
@@ -336,9 +509,55 @@ func getScannerOptions() []scanner.Option {
 	allExcludedRuleIDs = mergeWithoutDuplicates(allExcludedRuleIDs, tfsecConfig.ExcludedChecks)
 
 	options = append(options, scanner.OptionExcludeRules(allExcludedRuleIDs))
+	if tfsecConfig.RequireIgnoreJustification {
+		options = append(options, scanner.OptionRequireIgnoreJustification())
+	}
+	if tags, _ := getIncludedTags(); len(tags) > 0 {
+		options = append(options, scanner.OptionIncludeTags(tags))
+	}
+	if compliance != "" {
+		options = append(options, scanner.OptionCompliance(rule.Framework(compliance)))
+	}
 	return options
 }
 
+// getIncludedTags returns the tags given with --include-tags, or an error if any of them is not a known tag
+func getIncludedTags() ([]rule.Tag, error) {
+	if includeTags == "" {
+		return nil, nil
+	}
+	var tags []rule.Tag
+	for _, name := range strings.Split(includeTags, ",") {
+		tag := rule.Tag(strings.ToLower(strings.TrimSpace(name)))
+		if !tag.IsValid() {
+			return nil, fmt.Errorf("unknown tag '%s', must be one of %v", name, rule.ValidTags)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// checkSeverityOverrides warns about severity overrides in the config which name a rule that does not exist, or which
+// are not a valid severity, rather than silently having no effect
+func checkSeverityOverrides() {
+	defaults := make(map[string]severity.Severity)
+	for _, r := range scanner.GetRegisteredRules() {
+		defaults[r.ID] = r.DefaultSeverity
+	}
+	for id, sev := range tfsecConfig.SeverityOverrides {
+		override := severity.Severity(sev)
+		defaultSeverity, exists := defaults[id]
+		switch {
+		case !exists:
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: the severity override for '%s' does not match any check\n", id)
+		case !override.IsValid():
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: the severity override for '%s' is not a valid severity: '%s'\n", id, sev)
+		default:
+			debug.Log("Overriding the severity of %s from %s to %s", id, defaultSeverity, override)
+		}
+	}
+}
+
 func mergeWithoutDuplicates(left, right []string) []string {
 	all := append(left, right...)
 	var set = map[string]bool{}
@@ -355,7 +574,7 @@ func mergeWithoutDuplicates(left, right []string) []string {
 
 func allInfo(results []result.Result) bool {
 	for _, res := range results {
-		if res.Severity != severity.Info && res.Status != result.Passed && res.Status != result.Ignored {
+		if res.Severity != severity.Info && res.Status != result.Passed && res.Status != result.Ignored && res.Status != result.Unresolved && res.Status != result.Baselined {
 			return false
 		}
 	}
@@ -382,6 +601,15 @@ func updateResultSeverity(results []result.Result) []result.Result {
 	return overriddenResults
 }
 
+// formatReportsDiagnostics returns false for formats which cannot include diagnostics, so they are written to stderr
+func formatReportsDiagnostics() bool {
+	switch strings.ToLower(format) {
+	case "csv", "checkstyle", "junit":
+		return false
+	}
+	return true
+}
+
 func getFormatter() (formatters.Formatter, error) {
 	switch strings.ToLower(format) {
 	case "", "default":
@@ -420,11 +648,78 @@ func countPassedResults(results []result.Result) int {
 	return passed
 }
 
+func countUnresolvedResults(results []result.Result) int {
+	unresolved := 0
+
+	for _, res := range results {
+		if res.Status == result.Unresolved {
+			unresolved++
+		}
+	}
+
+	return unresolved
+}
+
+func countIgnoredResults(results []result.Result) int {
+	ignored := 0
+
+	for _, res := range results {
+		if res.Status == result.Ignored {
+			ignored++
+		}
+	}
+
+	return ignored
+}
+
+func countBaselinedResults(results []result.Result) int {
+	baselined := 0
+
+	for _, res := range results {
+		if res.Status == result.Baselined {
+			baselined++
+		}
+	}
+
+	return baselined
+}
+
+// applyBaseline writes the findings to a new baseline if requested, accepting all of them, or otherwise marks the
+// findings which are in the given baseline
+func applyBaseline(results []result.Result) ([]result.Result, error) {
+	var accepted *baseline.Baseline
+	switch {
+	case writeBaselineFile != "":
+		// the findings of a new baseline are accepted straight away, so the run which writes it succeeds
+		accepted = baseline.New(results)
+		if err := accepted.Write(writeBaselineFile); err != nil {
+			return nil, err
+		}
+		debug.Log("Wrote %d findings to baseline file %s", len(accepted.Entries), writeBaselineFile)
+	case baselineFile != "":
+		var err error
+		if accepted, err = baseline.Load(baselineFile); err != nil {
+			return nil, err
+		}
+	default:
+		return results, nil
+	}
+	return accepted.Apply(results), nil
+}
+
+// unusedTfvarsPresent returns true if there are tfvars files which terraform would not load automatically
 func unusedTfvarsPresent(checkDir string) bool {
 	glob := fmt.Sprintf("%s/*.tfvars", checkDir)
 	debug.Log("checking for tfvars files using glob: %s", glob)
-	if matches, err := filepath.Glob(glob); err == nil && len(matches) > 0 {
-		return true
+	matches, err := filepath.Glob(glob)
+	if err != nil {
+		return false
+	}
+	for _, match := range matches {
+		name := filepath.Base(match)
+		if name != "terraform.tfvars" && !strings.HasSuffix(name, ".auto.tfvars") {
+			return true
+		}
 	}
 	return false
 }
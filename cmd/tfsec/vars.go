@@ -0,0 +1,56 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+)
+
+// varsSources holds the values of the --tfvars-file and --var flags in the order they were provided, as terraform
+// gives precedence to whichever was specified last
+var varsSources []parser.VarsSource
+
+type tfvarsFileFlag struct {
+	paths []string
+}
+
+func (f *tfvarsFileFlag) String() string {
+	return strings.Join(f.paths, ",")
+}
+
+func (f *tfvarsFileFlag) Set(value string) error {
+	path, err := filepath.Abs(value)
+	if err != nil {
+		return err
+	}
+	f.paths = append(f.paths, path)
+	varsSources = append(varsSources, parser.VarsFile(path))
+	return nil
+}
+
+func (f *tfvarsFileFlag) Type() string {
+	return "stringArray"
+}
+
+type varFlag struct {
+	values []string
+}
+
+func (f *varFlag) String() string {
+	return strings.Join(f.values, ",")
+}
+
+func (f *varFlag) Set(value string) error {
+	source, err := parser.ParseVarFlag(value)
+	if err != nil {
+		return err
+	}
+	f.values = append(f.values, value)
+	varsSources = append(varsSources, source)
+	return nil
+}
+
+func (f *varFlag) Type() string {
+	return "stringArray"
+}
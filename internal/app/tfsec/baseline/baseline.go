@@ -0,0 +1,108 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/tfsec/tfsec/pkg/result"
+)
+
+// version is the version of the baseline file format
+const version = 1
+
+// Baseline is a set of accepted findings. Results which match an entry in the baseline are marked as baselined, so that
+// only new findings fail a scan.
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"results"`
+}
+
+// Entry is an accepted finding. Only the fingerprint is used to match results, the other fields make the baseline file
+// readable when it is reviewed.
+type Entry struct {
+	Fingerprint   string `json:"fingerprint"`
+	RuleID        string `json:"rule_id"`
+	Address       string `json:"address"`
+	AttributePath string `json:"attribute_path,omitempty"`
+	Root          string `json:"root,omitempty"`
+}
+
+// New creates a baseline accepting each of the failed results
+func New(results []result.Result) *Baseline {
+	entries := make(map[string]Entry)
+	for _, res := range results {
+		if res.Status != result.Failed {
+			continue
+		}
+		fingerprint := res.Fingerprint()
+		entries[fingerprint] = Entry{
+			Fingerprint:   fingerprint,
+			RuleID:        res.RuleID,
+			Address:       res.Address,
+			AttributePath: res.AttributePath,
+			Root:          res.Root,
+		}
+	}
+
+	b := &Baseline{
+		Version: version,
+		Entries: []Entry{},
+	}
+	for _, entry := range entries {
+		b.Entries = append(b.Entries, entry)
+	}
+	// entries are sorted so that the file only changes when the findings do
+	sort.Slice(b.Entries, func(i, j int) bool {
+		if b.Entries[i].RuleID != b.Entries[j].RuleID {
+			return b.Entries[i].RuleID < b.Entries[j].RuleID
+		}
+		return b.Entries[i].Fingerprint < b.Entries[j].Fingerprint
+	})
+	return b
+}
+
+// Load reads a baseline written by Write
+func Load(path string) (*Baseline, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file '%s': %s", path, err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(content, &b); err != nil {
+		return nil, fmt.Errorf("failed to load baseline file '%s': %s", path, err)
+	}
+	if b.Version != version {
+		return nil, fmt.Errorf("baseline file '%s' has unsupported version %d", path, b.Version)
+	}
+	return &b, nil
+}
+
+// Write saves the baseline as JSON
+func (b *Baseline) Write(path string) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, append(content, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write baseline file '%s': %s", path, err)
+	}
+	return nil
+}
+
+// Apply marks each failed result which is in the baseline as baselined. Other results are returned as they are.
+func (b *Baseline) Apply(results []result.Result) []result.Result {
+	accepted := make(map[string]bool)
+	for _, entry := range b.Entries {
+		accepted[entry.Fingerprint] = true
+	}
+	applied := make([]result.Result, 0, len(results))
+	for _, res := range results {
+		if res.Status == result.Failed && accepted[res.Fingerprint()] {
+			res.WithStatus(result.Baselined)
+		}
+		applied = append(applied, res)
+	}
+	return applied
+}
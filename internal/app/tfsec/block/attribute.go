@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -14,11 +15,12 @@ import (
 )
 
 type Attribute struct {
-	hclAttribute *hclsyntax.Attribute
-	ctx          *hcl.EvalContext
+	hclAttribute     *hcl.Attribute
+	ctx              *hcl.EvalContext
+	evaluationErrors *EvaluationErrors
 }
 
-func NewAttribute(attr *hclsyntax.Attribute, ctx *hcl.EvalContext) *Attribute {
+func NewAttribute(attr *hcl.Attribute, ctx *hcl.EvalContext) *Attribute {
 	return &Attribute{
 		hclAttribute: attr,
 		ctx:          ctx,
@@ -33,27 +35,168 @@ func (attr *Attribute) Type() cty.Type {
 	return attr.Value().Type()
 }
 
-func (attr *Attribute) Value() (ctyVal cty.Value) {
+// Value returns the value of the attribute, or cty.NilVal if it is not known or could not be evaluated
+func (attr *Attribute) Value() cty.Value {
 	if attr == nil {
 		return cty.NilVal
 	}
+	ctyVal, _, err := attr.value()
+	if err != nil || !ctyVal.IsKnown() {
+		return cty.NilVal
+	}
+	return ctyVal
+}
+
+// value evaluates the expression of the attribute. A panic while evaluating is recovered and returned as an error, and
+// collected so that it can be reported.
+func (attr *Attribute) value() (ctyVal cty.Value, diags hcl.Diagnostics, err error) {
 	defer func() {
-		if err := recover(); err != nil {
-			ctyVal = cty.NilVal
+		if recovered := recover(); recovered != nil {
+			ctyVal, diags = cty.NilVal, nil
+			err = fmt.Errorf("failed to evaluate %s: %v", attr.Name(), recovered)
+			attr.evaluationErrors.add(EvaluationError{Range: attr.Range(), Err: err})
 		}
 	}()
-	ctyVal, _ = attr.hclAttribute.Expr.Value(attr.ctx)
-	if !ctyVal.IsKnown() {
-		return cty.NilVal
+	ctyVal, diags = attr.hclAttribute.Expr.Value(attr.ctx)
+	return ctyVal, diags, nil
+}
+
+// EvaluationError is a panic which was recovered while evaluating an attribute
+type EvaluationError struct {
+	Range Range
+	Err   error
+}
+
+// EvaluationErrors collects the panics recovered while evaluating attributes, as callers of Value() have no way to see
+// them. Each project has its own, which is shared by its blocks, so that the panics can be reported with the diagnostics
+// of the project.
+type EvaluationErrors struct {
+	sync.Mutex
+	errors []EvaluationError
+}
+
+func (e *EvaluationErrors) add(err EvaluationError) {
+	if e == nil {
+		return
 	}
-	return ctyVal
+	e.Lock()
+	defer e.Unlock()
+	e.errors = append(e.errors, err)
+}
+
+// Take returns the panics recovered since it was last called
+func (e *EvaluationErrors) Take() []EvaluationError {
+	if e == nil {
+		return nil
+	}
+	e.Lock()
+	defer e.Unlock()
+	errs := e.errors
+	e.errors = nil
+	return errs
+}
+
+// ValueState describes whether the value of an attribute could be determined
+type ValueState string
+
+const (
+	// ValueKnown means the value was fully evaluated
+	ValueKnown ValueState = "known"
+	// ValueUnknown means the value depends on something which is not known until the configuration is applied, such as
+	// a data source, an attribute of another resource or a variable which has not been set
+	ValueUnknown ValueState = "unknown"
+	// ValueError means the expression could not be evaluated
+	ValueError ValueState = "error"
+)
+
+// Evaluation is the outcome of evaluating an attribute. Unlike Value(), it tells values which are not known apart from
+// expressions which could not be evaluated, and lists the references which could not be resolved.
+type Evaluation struct {
+	Value      cty.Value
+	State      ValueState
+	Unresolved []string
+	Err        error
+}
+
+// Evaluate evaluates the attribute. Values which are only partly known, such as a list containing an unknown element,
+// are unknown, although the known parts can still be inspected.
+func (attr *Attribute) Evaluate() Evaluation {
+
+	value, diags, err := attr.value()
+	if err != nil {
+		return Evaluation{Value: cty.NilVal, State: ValueError, Err: err}
+	}
+	if !diags.HasErrors() && value.IsWhollyKnown() {
+		return Evaluation{Value: value, State: ValueKnown}
+	}
+
+	// the references are only walked for values which are not known, as this is slow for large expressions
+	evaluation := Evaluation{
+		Value:      value,
+		State:      ValueUnknown,
+		Unresolved: attr.unresolvedReferences(),
+	}
+	if diags.HasErrors() && len(evaluation.Unresolved) == 0 {
+		// nothing the expression refers to is missing, so the expression itself is at fault
+		evaluation.State = ValueError
+		evaluation.Err = diags
+	}
+	return evaluation
+}
+
+// IsResolvable returns true if the value of the attribute is fully known
+func (attr *Attribute) IsResolvable() bool {
+	value, diags, err := attr.value()
+	return err == nil && !diags.HasErrors() && value.IsWhollyKnown()
+}
+
+// unresolvedReferences returns the references made by the attribute which have no value, or a value which is not known
+func (attr *Attribute) unresolvedReferences() []string {
+	var unresolved []string
+	seen := make(map[string]bool)
+	for _, traversal := range attr.References() {
+		if value, diags := traversal.TraverseAbs(attr.ctx); !diags.HasErrors() && value.IsWhollyKnown() {
+			continue
+		}
+		reference := traversalString(traversal)
+		if !seen[reference] {
+			seen[reference] = true
+			unresolved = append(unresolved, reference)
+		}
+	}
+	return unresolved
+}
+
+// traversalString writes a traversal as it appears in the configuration, e.g. data.x.y["key"].z
+func traversalString(traversal hcl.Traversal) string {
+	var builder strings.Builder
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			builder.WriteString(s.Name)
+		case hcl.TraverseAttr:
+			builder.WriteString("." + s.Name)
+		case hcl.TraverseIndex:
+			switch {
+			case s.Key.Type() == cty.String:
+				builder.WriteString(fmt.Sprintf("[%q]", s.Key.AsString()))
+			case s.Key.Type() == cty.Number:
+				builder.WriteString(fmt.Sprintf("[%s]", s.Key.AsBigFloat().Text('f', -1)))
+			default:
+				builder.WriteString("[?]")
+			}
+		case hcl.TraverseSplat:
+			builder.WriteString("[*]")
+		}
+	}
+	return builder.String()
 }
 
 func (attr *Attribute) Range() Range {
 	return Range{
-		Filename:  attr.hclAttribute.SrcRange.Filename,
-		StartLine: attr.hclAttribute.SrcRange.Start.Line,
-		EndLine:   attr.hclAttribute.SrcRange.End.Line,
+		Filename:  attr.hclAttribute.Range.Filename,
+		StartLine: attr.hclAttribute.Range.Start.Line,
+		EndLine:   attr.hclAttribute.Range.End.Line,
 	}
 }
 
@@ -341,19 +484,16 @@ func (attr *Attribute) GreaterThanOrEqualTo(checkValue interface{}) bool {
 }
 
 func (attr *Attribute) ReferencesDataBlock() bool {
-	switch t := attr.hclAttribute.Expr.(type) {
-	case *hclsyntax.ScopeTraversalExpr:
-		split := t.Traversal.SimpleSplit()
-		return split.Abs.RootName() == "data"
+	if traversal := attr.traversal(); traversal != nil {
+		return traversal.SimpleSplit().Abs.RootName() == "data"
 	}
 	return false
 }
 
 func (attr *Attribute) ReferenceAsString() string {
 	var refParts []string
-	switch t := attr.hclAttribute.Expr.(type) {
-	case *hclsyntax.ScopeTraversalExpr:
-		parts := t.Traversal.SimpleSplit()
+	if traversal := attr.traversal(); traversal != nil {
+		parts := traversal.SimpleSplit()
 		for _, p := range parts.Rel {
 			switch part := p.(type) {
 			case hcl.TraverseAttr:
@@ -366,3 +506,34 @@ func (attr *Attribute) ReferenceAsString() string {
 	}
 	return ""
 }
+
+// References returns all references made by the attribute's expression
+func (attr *Attribute) References() []hcl.Traversal {
+	return attr.hclAttribute.Expr.Variables()
+}
+
+// sourceExpression returns the expression of the attribute as written in the configuration
+func (attr *Attribute) sourceExpression() hcl.Expression {
+	expr := attr.hclAttribute.Expr
+	if value, ok := expr.(*valueExpr); ok && value.source != nil {
+		// resolved values keep the references made in the configuration
+		expr = value.source
+	}
+	return expr
+}
+
+// traversal returns the reference made by the attribute if its expression is a single reference, e.g. data.x.y
+func (attr *Attribute) traversal() hcl.Traversal {
+	expr := attr.sourceExpression()
+	switch t := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		return t.Traversal
+	case hclsyntax.Expression:
+		return nil
+	}
+	// JSON expressions make references using string interpolation e.g. "${data.x.y}"
+	if variables := expr.Variables(); len(variables) == 1 {
+		return variables[0]
+	}
+	return nil
+}
@@ -5,13 +5,17 @@ import (
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type Block struct {
 	hclBlock    *hcl.Block
 	evalContext *hcl.EvalContext
 	moduleBlock *Block
+	instanceKey cty.Value
+	expanded    bool
+	// where panics recovered while evaluating attributes of the block, and of the blocks derived from it, are collected
+	evaluationErrors *EvaluationErrors
 }
 
 func New(hclBlock *hcl.Block, ctx *hcl.EvalContext, moduleBlock *Block) *Block {
@@ -30,12 +34,83 @@ func (block *Block) AttachEvalContext(ctx *hcl.EvalContext) {
 	block.evalContext = ctx
 }
 
-func (block *Block) HasModuleBlock() bool {
-	return block.moduleBlock != nil
+func (block *Block) Context() *hcl.EvalContext {
+	return block.evalContext
+}
+
+// AttachEvaluationErrors sets where the panics recovered while evaluating the attributes of the block are collected
+func (block *Block) AttachEvaluationErrors(errs *EvaluationErrors) {
+	block.evaluationErrors = errs
+}
+
+// EvaluationErrors returns where the panics recovered while evaluating the attributes of the block are collected, which
+// is nil if they are not collected
+func (block *Block) EvaluationErrors() *EvaluationErrors {
+	return block.evaluationErrors
+}
+
+// derive creates a block which is nested in, or generated from, this block, and shares its evaluation errors
+func (block *Block) derive(hclBlock *hcl.Block, ctx *hcl.EvalContext) *Block {
+	derived := New(hclBlock, ctx, block.moduleBlock)
+	derived.evaluationErrors = block.evaluationErrors
+	return derived
+}
+
+func (block *Block) newAttribute(attr *hcl.Attribute) *Attribute {
+	attribute := NewAttribute(attr, block.evalContext)
+	attribute.evaluationErrors = block.evaluationErrors
+	return attribute
+}
+
+// ExpandCount creates a copy of the block representing a single instance of a counted block, with count.index set
+func (block *Block) ExpandCount(index int) *Block {
+	indexVal := cty.NumberIntVal(int64(index))
+	return block.expand(indexVal, map[string]cty.Value{
+		"count": cty.ObjectVal(map[string]cty.Value{
+			"index": indexVal,
+		}),
+	})
+}
+
+// ExpandForEach creates a copy of the block representing a single instance of a for_each block, with each.key and each.value set
+func (block *Block) ExpandForEach(key cty.Value, value cty.Value) *Block {
+	return block.expand(key, map[string]cty.Value{
+		"each": cty.ObjectVal(map[string]cty.Value{
+			"key":   key,
+			"value": value,
+		}),
+	})
+}
+
+func (block *Block) expand(key cty.Value, variables map[string]cty.Value) *Block {
+	var childCtx *hcl.EvalContext
+	if block.evalContext != nil {
+		childCtx = block.evalContext.NewChild()
+	} else {
+		childCtx = &hcl.EvalContext{}
+	}
+	childCtx.Variables = variables
+	clone := block.derive(block.hclBlock, childCtx)
+	clone.instanceKey = key
+	clone.expanded = true
+	return clone
 }
 
-func (block *Block) body() *hclsyntax.Body {
-	return block.hclBlock.Body.(*hclsyntax.Body)
+// IsExpanded returns true if the block is a single instance of a block using count or for_each
+func (block *Block) IsExpanded() bool {
+	return block.expanded
+}
+
+// InstanceKey returns the count index or for_each key of an expanded block, or cty.NilVal if the block has not been expanded
+func (block *Block) InstanceKey() cty.Value {
+	if !block.expanded {
+		return cty.NilVal
+	}
+	return block.instanceKey
+}
+
+func (block *Block) HasModuleBlock() bool {
+	return block.moduleBlock != nil
 }
 
 func (block *Block) Type() string {
@@ -50,7 +125,7 @@ func (block *Block) Range() Range {
 	if block == nil || block.hclBlock == nil {
 		return Range{}
 	}
-	r := block.body().SrcRange
+	r := bodyRange(block.hclBlock)
 	return Range{
 		Filename:  r.Filename,
 		StartLine: r.Start.Line,
@@ -72,17 +147,15 @@ func (block *Block) GetBlock(name string) *Block {
 	if block == nil || block.hclBlock == nil {
 		return nil
 	}
-	for _, child := range block.body().Blocks {
+	for _, child := range childBlocks(block.hclBlock.Body, name) {
 		if child.Type == name {
-			return New(child.AsHCLBlock(), block.evalContext, block.moduleBlock)
+			return block.derive(child, block.evalContext)
 		}
-		if child.Type == "dynamic" && len(child.Labels) == 1 && child.Labels[0] == name {
-			blocks := block.parseDynamicBlockResult(child)
-			if len(blocks) > 0 {
-				return blocks[0]
-			}
-			return nil
+		blocks := block.parseDynamicBlockResult(child)
+		if len(blocks) > 0 {
+			return blocks[0]
 		}
+		return nil
 	}
 	return nil
 }
@@ -92,8 +165,8 @@ func (block *Block) AllBlocks() Blocks {
 		return nil
 	}
 	var results []*Block
-	for _, child := range block.body().Blocks {
-		results = append(results, New(child.AsHCLBlock(), block.evalContext, block.moduleBlock))
+	for _, child := range childBlocks(block.hclBlock.Body, "") {
+		results = append(results, block.derive(child, block.evalContext))
 	}
 	return results
 }
@@ -103,39 +176,64 @@ func (block *Block) GetBlocks(name string) Blocks {
 		return nil
 	}
 	var results []*Block
-	for _, child := range block.body().Blocks {
+	for _, child := range childBlocks(block.hclBlock.Body, name) {
 		if child.Type == name {
-			results = append(results, New(child.AsHCLBlock(), block.evalContext, block.moduleBlock))
-		}
-		if child.Type == "dynamic" && len(child.Labels) == 1 && child.Labels[0] == name {
-			dynamics := block.parseDynamicBlockResult(child)
-			results = append(results, dynamics...)
-
+			results = append(results, block.derive(child, block.evalContext))
+			continue
 		}
+		dynamics := block.parseDynamicBlockResult(child)
+		results = append(results, dynamics...)
 	}
 	return results
 }
 
-func (block *Block) parseDynamicBlockResult(dynamic *hclsyntax.Block) Blocks {
+func (block *Block) parseDynamicBlockResult(dynamic *hcl.Block) Blocks {
 
 	var results Blocks
 
-	wrapped := New(dynamic.AsHCLBlock(), block.evalContext, block.moduleBlock)
+	wrapped := block.derive(dynamic, block.evalContext)
 
 	forEach := wrapped.GetAttribute("for_each")
 	if forEach == nil {
 		return nil
 	}
 
-	contentBlock := wrapped.GetBlock("content")
-	if contentBlock == nil {
+	contentBlocks := childBlocks(dynamic.Body, "content")
+	if len(contentBlocks) == 0 || contentBlocks[0].Type != "content" {
+		return nil
+	}
+	content := contentBlocks[0]
+
+	forEachVal := forEach.Value()
+	if forEachVal.IsNull() || !forEachVal.CanIterateElements() {
 		return nil
 	}
 
-	values := forEach.Value().AsValueSlice()
-	for range values {
-		clone := *contentBlock
-		results = append(results, &clone)
+	iteratorName := dynamic.Labels[0]
+	if iterator := wrapped.GetAttribute("iterator"); iterator != nil {
+		if traversal, diags := hcl.AbsTraversalForExpr(iterator.hclAttribute.Expr); !diags.HasErrors() {
+			iteratorName = traversal.RootName()
+		}
+	}
+
+	for it := forEachVal.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+
+		childCtx := &hcl.EvalContext{}
+		if block.evalContext != nil {
+			childCtx = block.evalContext.NewChild()
+		}
+		childCtx.Variables = map[string]cty.Value{
+			iteratorName: cty.ObjectVal(map[string]cty.Value{
+				"key":   key,
+				"value": value,
+			}),
+		}
+
+		// the generated block takes the type named by the dynamic block label, e.g. dynamic "ingress" creates ingress blocks
+		hclBlock := *content
+		hclBlock.Type = dynamic.Labels[0]
+		results = append(results, block.derive(&hclBlock, childCtx))
 	}
 
 	return results
@@ -146,8 +244,8 @@ func (block *Block) GetAttributes() []*Attribute {
 	if block == nil || block.hclBlock == nil {
 		return nil
 	}
-	for _, attr := range block.body().Attributes {
-		results = append(results, NewAttribute(attr, block.evalContext))
+	for _, attr := range bodyAttributes(block.hclBlock.Body) {
+		results = append(results, block.newAttribute(attr))
 	}
 	return results
 }
@@ -156,21 +254,41 @@ func (block *Block) GetAttribute(name string) *Attribute {
 	if block == nil || block.hclBlock == nil {
 		return nil
 	}
-	for _, attr := range block.body().Attributes {
-		if attr.Name == name {
-			return NewAttribute(attr, block.evalContext)
-		}
+	if attr := bodyAttribute(block.hclBlock.Body, name); attr != nil {
+		return block.newAttribute(attr)
 	}
 	return nil
 }
 
+// References returns the references made by the expressions in the block and its nested blocks, e.g. var.x
+func (block *Block) References() []hcl.Traversal {
+	if block == nil || block.hclBlock == nil {
+		return nil
+	}
+	return bodyReferences(block.hclBlock.Body)
+}
+
 // LocalName is the name relative to the current module
 func (block *Block) LocalName() string {
 	var prefix string
 	if block.Type() != "resource" {
 		prefix = block.Type() + "."
 	}
-	return prefix + strings.Join(block.Labels(), ".")
+	return prefix + strings.Join(block.Labels(), ".") + block.instanceSuffix()
+}
+
+func (block *Block) instanceSuffix() string {
+	if !block.expanded {
+		return ""
+	}
+	switch block.instanceKey.Type() {
+	case cty.Number:
+		index, _ := block.instanceKey.AsBigFloat().Int64()
+		return fmt.Sprintf("[%d]", index)
+	case cty.String:
+		return fmt.Sprintf("[%q]", block.instanceKey.AsString())
+	}
+	return ""
 }
 
 func (block *Block) FullName() string {
@@ -186,6 +304,15 @@ func (block *Block) FullName() string {
 	return block.LocalName()
 }
 
+// Address returns the terraform address of the block, e.g. module.a["x"].aws_s3_bucket.b[0]. Blocks which terraform
+// does not address, such as variables, use their local name.
+func (block *Block) Address() string {
+	if block.moduleBlock != nil {
+		return block.moduleBlock.Address() + "." + block.LocalName()
+	}
+	return block.LocalName()
+}
+
 func (block *Block) TypeLabel() string {
 	if len(block.Labels()) > 0 {
 		return block.Labels()[0]
@@ -232,3 +359,44 @@ func (block *Block) IsResourceType(resourceType string) bool {
 func (block *Block) IsEmpty() bool {
 	return len(block.AllBlocks()) == 0 && len(block.GetAttributes()) == 0
 }
+
+// AttributePath returns the path within the block to the attribute or nested block at the given range, e.g.
+// ingress[1].cidr_blocks, so that part of a block can be identified without relying on line numbers. Nested blocks are
+// indexed when there is more than one of the same type. An empty string is returned for the block itself, or for a range
+// which is not within the block.
+func (block *Block) AttributePath(r Range) string {
+	if r.Filename == "" {
+		return ""
+	}
+	for _, attr := range block.GetAttributes() {
+		if rangeContains(attr.Range(), r) {
+			return attr.Name()
+		}
+	}
+	children := block.AllBlocks()
+	counts := make(map[string]int)
+	for _, child := range children {
+		counts[child.Type()]++
+	}
+	indices := make(map[string]int)
+	for _, child := range children {
+		name := child.Type()
+		index := indices[name]
+		indices[name]++
+		if !rangeContains(child.Range(), r) {
+			continue
+		}
+		if counts[name] > 1 {
+			name = fmt.Sprintf("%s[%d]", name, index)
+		}
+		if path := child.AttributePath(r); path != "" {
+			return name + "." + path
+		}
+		return name
+	}
+	return ""
+}
+
+func rangeContains(outer Range, inner Range) bool {
+	return outer.Filename == inner.Filename && outer.StartLine <= inner.StartLine && outer.EndLine >= inner.EndLine
+}
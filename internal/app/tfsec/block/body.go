@@ -0,0 +1,214 @@
+package block
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Blocks can be written in either native HCL syntax or JSON syntax (*.tf.json). Native syntax bodies can be read
+// directly, whereas JSON bodies are ambiguous without a schema - a JSON object could be either an attribute or a
+// nested block. For JSON bodies we therefore ask for the attributes and blocks we are interested in by name, which
+// lets hcl interpret them accordingly.
+
+const dynamicBlockType = "dynamic"
+
+// bodyRange returns the range of the entire block, including its body
+func bodyRange(hclBlock *hcl.Block) hcl.Range {
+	return rangeOfBody(hclBlock.Body, hclBlock.DefRange)
+}
+
+func rangeOfBody(body hcl.Body, defRange hcl.Range) hcl.Range {
+	switch b := body.(type) {
+	case *hclsyntax.Body:
+		return b.SrcRange
+	case *mergedBody:
+		// an overridden block is still located where it was originally defined
+		return rangeOfBody(b.base, defRange)
+	case *valueBody:
+		if b.source != nil {
+			return rangeOfBody(b.source, defRange)
+		}
+		return defRange
+	}
+	return hcl.RangeBetween(defRange, body.MissingItemRange())
+}
+
+// childBlocks returns the nested blocks of the given type (or all nested blocks if no type is given) in source order.
+// Dynamic blocks which generate blocks of the given type are also returned.
+func childBlocks(body hcl.Body, blockType string) hcl.Blocks {
+
+	if merged, ok := body.(*mergedBody); ok {
+		return filterBlocks(mergeBlocks(childBlocks(merged.base, ""), childBlocks(merged.override, "")), blockType)
+	}
+
+	if value, ok := body.(*valueBody); ok {
+		return value.blocks(blockType)
+	}
+
+	if nativeBody, ok := body.(*hclsyntax.Body); ok {
+		var results hcl.Blocks
+		for _, child := range nativeBody.Blocks {
+			results = append(results, child.AsHCLBlock())
+		}
+		return filterBlocks(results, blockType)
+	}
+
+	var blockTypes []string
+	if blockType != "" {
+		blockTypes = []string{blockType}
+	} else {
+		blockTypes = possibleJSONBlockTypes(body)
+	}
+
+	schema := &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{
+				Type:       dynamicBlockType,
+				LabelNames: []string{"name"},
+			},
+		},
+	}
+	for _, t := range blockTypes {
+		if t != dynamicBlockType {
+			schema.Blocks = append(schema.Blocks, hcl.BlockHeaderSchema{Type: t})
+		}
+	}
+
+	content, _, _ := body.PartialContent(schema)
+	if content == nil {
+		return nil
+	}
+
+	results := filterBlocks(content.Blocks, blockType)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].DefRange.Start.Byte < results[j].DefRange.Start.Byte
+	})
+	return results
+}
+
+// filterBlocks returns the blocks of the given type, including dynamic blocks which generate that type
+func filterBlocks(blocks hcl.Blocks, blockType string) hcl.Blocks {
+	if blockType == "" {
+		return blocks
+	}
+	var results hcl.Blocks
+	for _, child := range blocks {
+		if child.Type == blockType || isDynamicBlockOfType(child, blockType) {
+			results = append(results, child)
+		}
+	}
+	return results
+}
+
+func isDynamicBlockOfType(hclBlock *hcl.Block, blockType string) bool {
+	return hclBlock.Type == dynamicBlockType && len(hclBlock.Labels) == 1 && hclBlock.Labels[0] == blockType
+}
+
+// possibleJSONBlockTypes returns the names of JSON properties which could represent nested blocks, i.e. those with an
+// object value, or an array of objects
+func possibleJSONBlockTypes(body hcl.Body) []string {
+	attributes, _ := body.JustAttributes()
+	var names []string
+	for name, attr := range attributes {
+		if isJSONObject(attr.Expr) {
+			names = append(names, name)
+			continue
+		}
+		items, diags := hcl.ExprList(attr.Expr)
+		if diags.HasErrors() || len(items) == 0 {
+			continue
+		}
+		allObjects := true
+		for _, item := range items {
+			if !isJSONObject(item) {
+				allObjects = false
+				break
+			}
+		}
+		if allObjects {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isJSONObject(expr hcl.Expression) bool {
+	_, diags := hcl.ExprMap(expr)
+	return !diags.HasErrors()
+}
+
+// bodyAttributes returns all attributes of the body. For JSON bodies this includes any properties representing nested
+// blocks, as these cannot be distinguished from object attributes without a schema.
+func bodyAttributes(body hcl.Body) hcl.Attributes {
+	if merged, ok := body.(*mergedBody); ok {
+		attributes := bodyAttributes(merged.base)
+		for name, attr := range bodyAttributes(merged.override) {
+			attributes[name] = attr
+		}
+		return attributes
+	}
+	if value, ok := body.(*valueBody); ok {
+		return value.attributes()
+	}
+	if nativeBody, ok := body.(*hclsyntax.Body); ok {
+		attributes := make(hcl.Attributes, len(nativeBody.Attributes))
+		for name, attr := range nativeBody.Attributes {
+			attributes[name] = attr.AsHCLAttribute()
+		}
+		return attributes
+	}
+	attributes, _ := body.JustAttributes()
+	return attributes
+}
+
+// bodyAttribute returns the named attribute of the body, or nil if it is not set
+func bodyAttribute(body hcl.Body, name string) *hcl.Attribute {
+	if merged, ok := body.(*mergedBody); ok {
+		if attr := bodyAttribute(merged.override, name); attr != nil {
+			return attr
+		}
+		return bodyAttribute(merged.base, name)
+	}
+	if value, ok := body.(*valueBody); ok {
+		return value.attributes()[name]
+	}
+	if nativeBody, ok := body.(*hclsyntax.Body); ok {
+		if attr, exists := nativeBody.Attributes[name]; exists {
+			return attr.AsHCLAttribute()
+		}
+		return nil
+	}
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name: name,
+			},
+		},
+	})
+	if content == nil {
+		return nil
+	}
+	return content.Attributes[name]
+}
+
+// bodyReferences returns the references made by all attributes of the body, including those of nested blocks
+func bodyReferences(body hcl.Body) []hcl.Traversal {
+	attributes := bodyAttributes(body)
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var references []hcl.Traversal
+	for _, name := range names {
+		references = append(references, attributes[name].Expr.Variables()...)
+	}
+	for _, child := range childBlocks(body, "") {
+		references = append(references, bodyReferences(child.Body)...)
+	}
+	return references
+}
@@ -0,0 +1,114 @@
+package block
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// MergeBodies combines the body of a block with the body of a block which overrides it (e.g. from an override.tf
+// file) using terraform's override rules. Attributes in the override replace those in the base body, and nested blocks
+// in the override replace all nested blocks of the same type in the base body. Each attribute and block keeps the
+// source range of wherever it was defined, so results point at the effective value.
+func MergeBodies(base hcl.Body, override hcl.Body) hcl.Body {
+	return &mergedBody{
+		base:     base,
+		override: override,
+	}
+}
+
+type mergedBody struct {
+	base     hcl.Body
+	override hcl.Body
+}
+
+func (m *mergedBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, _, diags := m.PartialContent(schema)
+	return content, diags
+}
+
+func (m *mergedBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	// required attributes may be defined in either body, so neither body can require them individually
+	optionalSchema := &hcl.BodySchema{
+		Blocks: schema.Blocks,
+	}
+	for _, attr := range schema.Attributes {
+		optionalSchema.Attributes = append(optionalSchema.Attributes, hcl.AttributeSchema{
+			Name: attr.Name,
+		})
+	}
+
+	baseContent, baseRemain, baseDiags := m.base.PartialContent(optionalSchema)
+	diags = append(diags, baseDiags...)
+	overrideContent, overrideRemain, overrideDiags := m.override.PartialContent(optionalSchema)
+	diags = append(diags, overrideDiags...)
+
+	content := &hcl.BodyContent{
+		Attributes:       make(hcl.Attributes),
+		MissingItemRange: baseContent.MissingItemRange,
+	}
+	for name, attr := range baseContent.Attributes {
+		content.Attributes[name] = attr
+	}
+	for name, attr := range overrideContent.Attributes {
+		content.Attributes[name] = attr
+	}
+	content.Blocks = mergeBlocks(baseContent.Blocks, overrideContent.Blocks)
+
+	for _, attr := range schema.Attributes {
+		if _, exists := content.Attributes[attr.Name]; attr.Required && !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing required argument",
+				Detail:   "The argument \"" + attr.Name + "\" is required, but no definition was found.",
+				Subject:  content.MissingItemRange.Ptr(),
+			})
+		}
+	}
+
+	return content, MergeBodies(baseRemain, overrideRemain), diags
+}
+
+func (m *mergedBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	baseAttributes, baseDiags := m.base.JustAttributes()
+	diags = append(diags, baseDiags...)
+	overrideAttributes, overrideDiags := m.override.JustAttributes()
+	diags = append(diags, overrideDiags...)
+
+	attributes := make(hcl.Attributes)
+	for name, attr := range baseAttributes {
+		attributes[name] = attr
+	}
+	for name, attr := range overrideAttributes {
+		attributes[name] = attr
+	}
+	return attributes, diags
+}
+
+func (m *mergedBody) MissingItemRange() hcl.Range {
+	return m.base.MissingItemRange()
+}
+
+// mergeBlocks replaces all base blocks of any type which is present in the override blocks. Dynamic blocks are
+// treated as blocks of the type they generate.
+func mergeBlocks(base hcl.Blocks, override hcl.Blocks) hcl.Blocks {
+	overriddenTypes := make(map[string]bool)
+	for _, b := range override {
+		overriddenTypes[generatedBlockType(b)] = true
+	}
+	var merged hcl.Blocks
+	for _, b := range base {
+		if !overriddenTypes[generatedBlockType(b)] {
+			merged = append(merged, b)
+		}
+	}
+	return append(merged, override...)
+}
+
+func generatedBlockType(b *hcl.Block) string {
+	if b.Type == dynamicBlockType && len(b.Labels) == 1 {
+		return b.Labels[0]
+	}
+	return b.Type
+}
@@ -0,0 +1,86 @@
+package block
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModuleBlock returns the module block which called the module the block belongs to, or nil for the root module
+func (block *Block) ModuleBlock() *Block {
+	return block.moduleBlock
+}
+
+// ProviderReference returns the local name and alias of the provider configuration used by a resource or data block.
+// This is the configuration given by its provider attribute, or otherwise the default configuration of the provider
+// its type belongs to, e.g. aws for aws_s3_bucket.
+func (block *Block) ProviderReference() (string, string) {
+	if attr := block.GetAttribute("provider"); attr != nil {
+		if name, alias := providerAddress(attr.sourceExpression()); name != "" {
+			return name, alias
+		}
+	}
+	name := block.TypeLabel()
+	if i := strings.Index(name, "_"); i > 0 {
+		name = name[:i]
+	}
+	return name, ""
+}
+
+// ModuleProviders returns the provider configurations a module block passes to its module, mapping the address used
+// within the module to the address of the configuration in the calling module, e.g. aws => aws.eu. The second value is
+// false if the module block has no providers attribute, in which case the module inherits the default configurations.
+func (block *Block) ModuleProviders() (map[string]string, bool) {
+	attr := block.GetAttribute("providers")
+	if attr == nil {
+		return nil, false
+	}
+	providers := make(map[string]string)
+	pairs, diags := hcl.ExprMap(attr.sourceExpression())
+	if diags.HasErrors() {
+		return providers, true
+	}
+	for _, pair := range pairs {
+		childName, childAlias := providerAddress(pair.Key)
+		parentName, parentAlias := providerAddress(pair.Value)
+		if childName == "" || parentName == "" {
+			continue
+		}
+		providers[joinProviderAddress(childName, childAlias)] = joinProviderAddress(parentName, parentAlias)
+	}
+	return providers, true
+}
+
+// providerAddress reads a reference to a provider configuration, such as aws or aws.eu, as its name and alias
+func providerAddress(expr hcl.Expression) (string, string) {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		// JSON configuration and object keys can give the address as a string
+		value, diags := expr.Value(nil)
+		if diags.HasErrors() || !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+			return "", ""
+		}
+		parts := strings.SplitN(value.AsString(), ".", 2)
+		if len(parts) == 1 {
+			return parts[0], ""
+		}
+		return parts[0], parts[1]
+	}
+	switch len(traversal) {
+	case 1:
+		return traversal.RootName(), ""
+	case 2:
+		if attr, ok := traversal[1].(hcl.TraverseAttr); ok {
+			return traversal.RootName(), attr.Name
+		}
+	}
+	return "", ""
+}
+
+func joinProviderAddress(name string, alias string) string {
+	if alias == "" {
+		return name
+	}
+	return name + "." + alias
+}
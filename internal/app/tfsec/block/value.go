@@ -0,0 +1,198 @@
+package block
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// NewValueBody creates a body from a resolved object value, such as the attributes of a resource in a terraform plan.
+// Nested blocks are represented in such values as lists of objects. If the configuration body which produced the value
+// is available, it is used to tell nested blocks from attributes and to locate each of them in the source. Otherwise
+// everything is located at defRange.
+func NewValueBody(value cty.Value, source hcl.Body, defRange hcl.Range) hcl.Body {
+	return &valueBody{
+		value:    value,
+		source:   source,
+		defRange: defRange,
+	}
+}
+
+type valueBody struct {
+	value    cty.Value
+	source   hcl.Body
+	defRange hcl.Range
+}
+
+func (v *valueBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, _, diags := v.PartialContent(schema)
+	return content, diags
+}
+
+func (v *valueBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	content := &hcl.BodyContent{
+		Attributes:       make(hcl.Attributes),
+		MissingItemRange: v.MissingItemRange(),
+	}
+	attributes := v.attributes()
+	for _, attrSchema := range schema.Attributes {
+		if attr, exists := attributes[attrSchema.Name]; exists {
+			content.Attributes[attrSchema.Name] = attr
+		}
+	}
+	for _, blockSchema := range schema.Blocks {
+		content.Blocks = append(content.Blocks, v.blocks(blockSchema.Type)...)
+	}
+	return content, v, nil
+}
+
+func (v *valueBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	return v.attributes(), nil
+}
+
+func (v *valueBody) MissingItemRange() hcl.Range {
+	if v.source != nil {
+		return v.source.MissingItemRange()
+	}
+	return v.defRange
+}
+
+func (v *valueBody) values() map[string]cty.Value {
+	if v.value.IsNull() || !v.value.IsKnown() || !(v.value.Type().IsObjectType() || v.value.Type().IsMapType()) {
+		return nil
+	}
+	return v.value.AsValueMap()
+}
+
+func (v *valueBody) attributes() hcl.Attributes {
+	attributes := make(hcl.Attributes)
+	for name, val := range v.values() {
+		if val.IsNull() {
+			continue
+		}
+		sourceAttr := v.sourceAttribute(name)
+		if sourceAttr == nil && isBlockLike(val) {
+			// either nested blocks, or an empty list which could be unset blocks or an empty attribute
+			continue
+		}
+		rng := v.defRange
+		var sourceExpr hcl.Expression
+		if sourceAttr != nil {
+			rng = sourceAttr.Range
+			sourceExpr = sourceAttr.Expr
+		}
+		attributes[name] = &hcl.Attribute{
+			Name:      name,
+			Expr:      &valueExpr{value: val, rng: rng, source: sourceExpr},
+			Range:     rng,
+			NameRange: rng,
+		}
+	}
+	return attributes
+}
+
+// blocks returns the nested blocks of the given type, or all nested blocks if no type is given
+func (v *valueBody) blocks(blockType string) hcl.Blocks {
+	values := v.values()
+	var names []string
+	for name, val := range values {
+		if (blockType == "" || name == blockType) && v.isNestedBlock(name, val) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var results hcl.Blocks
+	for _, name := range names {
+		var sourceBlocks hcl.Blocks
+		if v.source != nil {
+			sourceBlocks = childBlocks(v.source, name)
+		}
+		i := 0
+		for it := values[name].ElementIterator(); it.Next(); i++ {
+			_, element := it.Element()
+			rng := v.defRange
+			var source hcl.Body
+			if len(sourceBlocks) > 0 {
+				// blocks generated by a dynamic block are all located at the dynamic block
+				sourceBlock := sourceBlocks[len(sourceBlocks)-1]
+				if i < len(sourceBlocks) {
+					sourceBlock = sourceBlocks[i]
+				}
+				rng = sourceBlock.DefRange
+				source = sourceBlock.Body
+				if sourceBlock.Type == dynamicBlockType {
+					source = nil
+					if content := childBlocks(sourceBlock.Body, "content"); len(content) > 0 {
+						source = content[0].Body
+					}
+				}
+			}
+			results = append(results, &hcl.Block{
+				Type:      name,
+				Body:      NewValueBody(element, source, rng),
+				DefRange:  rng,
+				TypeRange: rng,
+			})
+		}
+	}
+	return results
+}
+
+// isNestedBlock returns true if the named value represents nested blocks rather than an attribute
+func (v *valueBody) isNestedBlock(name string, val cty.Value) bool {
+	return v.sourceAttribute(name) == nil && isBlockLike(val) && val.LengthInt() > 0
+}
+
+func (v *valueBody) sourceAttribute(name string) *hcl.Attribute {
+	if v.source == nil {
+		return nil
+	}
+	return bodyAttribute(v.source, name)
+}
+
+// isBlockLike returns true if the value could represent nested blocks, i.e. it is a collection of objects
+func isBlockLike(val cty.Value) bool {
+	if val.IsNull() || !val.IsKnown() {
+		return false
+	}
+	t := val.Type()
+	if !t.IsListType() && !t.IsSetType() && !t.IsTupleType() {
+		return false
+	}
+	for it := val.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		if element.IsNull() || !(element.Type().IsObjectType() || element.Type().IsMapType()) {
+			return false
+		}
+	}
+	return true
+}
+
+// valueExpr is an expression with a resolved value. Where the expression which produced the value is known, its
+// references are kept, so that references between blocks can still be followed.
+type valueExpr struct {
+	value  cty.Value
+	rng    hcl.Range
+	source hcl.Expression
+}
+
+func (e *valueExpr) Value(_ *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	return e.value, nil
+}
+
+func (e *valueExpr) Variables() []hcl.Traversal {
+	if e.source == nil {
+		return nil
+	}
+	return e.source.Variables()
+}
+
+func (e *valueExpr) Range() hcl.Range {
+	return e.rng
+}
+
+func (e *valueExpr) StartRange() hcl.Range {
+	return e.rng
+}
@@ -14,6 +14,9 @@ import (
 type Config struct {
 	SeverityOverrides map[string]string `json:"severity_overrides,omitempty" yaml:"severity_overrides,omitempty"`
 	ExcludedChecks    []string          `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	Workspace         string            `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+	// RequireIgnoreJustification means tfsec:ignore comments must give a reason, e.g. tfsec:ignore:AWS002 -- reason
+	RequireIgnoreJustification bool `json:"require_ignore_justification,omitempty" yaml:"require_ignore_justification,omitempty"`
 }
 
 func LoadConfig(configFilePath string) (*Config, error) {
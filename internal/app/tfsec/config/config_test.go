@@ -55,6 +55,15 @@ func TestExcludesElementsFromJSON(t *testing.T) {
 	assert.Contains(t, c.ExcludedChecks, "DP001")
 }
 
+func TestWorkspaceFromYAML(t *testing.T) {
+	content := `
+workspace: prod
+`
+	c := load(t, "config.yaml", content)
+
+	assert.Equal(t, "prod", c.Workspace)
+}
+
 func load(t *testing.T, filename, content string) *config.Config {
 	dir, err := ioutil.TempDir("", "")
 	require.NoError(t, err)
@@ -69,3 +78,12 @@ func load(t *testing.T, filename, content string) *config.Config {
 
 	return c
 }
+
+func TestRequireIgnoreJustificationFromYAML(t *testing.T) {
+	content := `
+require_ignore_justification: true
+`
+	c := load(t, "config.yaml", content)
+
+	assert.True(t, c.RequireIgnoreJustification)
+}
@@ -129,9 +129,10 @@ func processFoundChecks(checks ChecksFile) {
 					Impact:     customCheck.Impact,
 					Resolution: customCheck.Resolution,
 				},
-				Provider:       provider.CustomProvider,
-				RequiredTypes:  customCheck.RequiredTypes,
-				RequiredLabels: customCheck.RequiredLabels,
+				Provider:        provider.CustomProvider,
+				DefaultSeverity: customCheck.Severity,
+				RequiredTypes:   customCheck.RequiredTypes,
+				RequiredLabels:  customCheck.RequiredLabels,
 				CheckFunc: func(set result.Set, rootBlock *block.Block, ctx *hclcontext.Context) {
 					matchSpec := customCheck.MatchSpec
 					if !evalMatchSpec(rootBlock, matchSpec, ctx) {
@@ -230,20 +231,12 @@ func checkTags(block *block.Block, spec *MatchSpec, ctx *hclcontext.Context) boo
 		}
 	}
 
-	var alias string
-	if block.HasChild("provider") {
-		alias = block.GetAttribute("provider").ReferenceAsString()
-	}
-
-	awsProviders := ctx.GetProviderBlocksByProvider("aws", alias)
-	for _, providerBlock := range awsProviders {
-		if providerBlock.HasChild("default_tags") {
-			defaultTags := providerBlock.GetBlock("default_tags")
-			if defaultTags.HasChild("tags") {
-				tags := defaultTags.GetAttribute("tags")
-				if tags.Contains(expectedTag) {
-					return true
-				}
+	if providerBlock := ctx.GetProviderConfig(block); providerBlock != nil && providerBlock.HasChild("default_tags") {
+		defaultTags := providerBlock.GetBlock("default_tags")
+		if defaultTags.HasChild("tags") {
+			tags := defaultTags.GetAttribute("tags")
+			if tags.Contains(expectedTag) {
+				return true
 			}
 		}
 	}
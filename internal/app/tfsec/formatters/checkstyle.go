@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"io"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 )
 
@@ -26,7 +27,7 @@ type checkstyleOutput struct {
 	Files   []checkstyleFile `xml:"file"`
 }
 
-func FormatCheckStyle(w io.Writer, results []result.Result, _ string, _ ...FormatterOption) error {
+func FormatCheckStyle(w io.Writer, results []result.Result, _ diagnostic.Diagnostics, _ string, _ ...FormatterOption) error {
 
 	output := checkstyleOutput{}
 
@@ -40,17 +41,23 @@ func FormatCheckStyle(w io.Writer, results []result.Result, _ string, _ ...Forma
 		if len(res.Links) > 0 {
 			link = res.Links[0]
 		}
+		// results without a file are grouped by resource address instead
+		name := res.Range.Filename
+		if !hasFileLocation(res) {
+			name = res.Address
+		}
+
 		fileResults := append(
-			files[res.Range.Filename],
+			files[name],
 			checkstyleResult{
 				Rule:     res.RuleID,
 				Line:     res.Range.StartLine,
-				Severity: string(res.Severity),
+				Severity: checkstyleSeverity(res),
 				Message:  res.Description,
 				Link:     link,
 			},
 		)
-		files[res.Range.Filename] = fileResults
+		files[name] = fileResults
 	}
 
 	for name, fileResults := range files {
@@ -72,3 +79,12 @@ func FormatCheckStyle(w io.Writer, results []result.Result, _ string, _ ...Forma
 
 	return xmlEncoder.Encode(output)
 }
+
+// checkstyleSeverity reports ignored and baselined checks, and checks which could not be made, as info, as they are not
+// problems
+func checkstyleSeverity(res result.Result) string {
+	if res.Status == result.Unresolved || res.Status == result.Ignored || res.Status == result.Baselined {
+		return "info"
+	}
+	return string(res.Severity)
+}
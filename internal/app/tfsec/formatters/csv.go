@@ -6,13 +6,14 @@ import (
 	"io"
 	"strconv"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 )
 
-func FormatCSV(w io.Writer, results []result.Result, _ string, _ ...FormatterOption) error {
+func FormatCSV(w io.Writer, results []result.Result, _ diagnostic.Diagnostics, _ string, _ ...FormatterOption) error {
 
 	records := [][]string{
-		{"file", "start_line", "end_line", "rule_id", "severity", "description", "link", "passed"},
+		{"file", "start_line", "end_line", "rule_id", "severity", "description", "link", "passed", "address", "root"},
 	}
 
 	for _, res := range results {
@@ -29,6 +30,8 @@ func FormatCSV(w io.Writer, results []result.Result, _ string, _ ...FormatterOpt
 			res.Description,
 			link,
 			strconv.FormatBool(res.Status == result.Passed),
+			res.Address,
+			res.Root,
 		})
 	}
 
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 
 	severity2 "github.com/tfsec/tfsec/pkg/severity"
@@ -18,7 +19,7 @@ import (
 	"github.com/liamg/tml"
 )
 
-func FormatDefault(_ io.Writer, results []result.Result, _ string, options ...FormatterOption) error {
+func FormatDefault(_ io.Writer, results []result.Result, diagnostics diagnostic.Diagnostics, _ string, options ...FormatterOption) error {
 
 	showStatistics := true
 	showSuccessOutput := true
@@ -36,6 +37,8 @@ func FormatDefault(_ io.Writer, results []result.Result, _ string, options ...Fo
 		}
 	}
 
+	printDiagnostics(diagnostics)
+
 	if len(results) == 0 || len(results) == countPassedResults(results) {
 		if showStatistics {
 			_ = tml.Printf("\n")
@@ -57,7 +60,11 @@ func FormatDefault(_ io.Writer, results []result.Result, _ string, options ...Fo
 	}
 
 	fmt.Println("")
+	results = baselinedLast(results)
 	for i, res := range results {
+		if res.Status == result.Baselined && (i == 0 || results[i-1].Status != result.Baselined) {
+			_ = tml.Printf("<white>The following findings are accepted by the baseline</white>\n\n")
+		}
 		resultHeader := fmt.Sprintf("<underline>Check %d</underline>\n", i+1)
 
 		if includePassedChecks && res.Status == result.Passed {
@@ -66,6 +73,14 @@ func FormatDefault(_ io.Writer, results []result.Result, _ string, options ...Fo
 		} else {
 			terminal.PrintErrorf(resultHeader)
 			severity = severityFormat[res.Severity]
+			switch res.Status {
+			case result.Unresolved:
+				severity = tml.Sprintf("<white>UNRESOLVED</white>")
+			case result.Ignored:
+				severity = tml.Sprintf("<white>IGNORED</white>")
+			case result.Baselined:
+				severity = tml.Sprintf("<white>BASELINED</white>")
+			}
 		}
 
 		_ = tml.Printf(`
@@ -73,8 +88,14 @@ func FormatDefault(_ io.Writer, results []result.Result, _ string, options ...Fo
   <blue>%s</blue>
 
 
-`, res.RuleID, severity, res.Description, res.Range.String())
+`, res.RuleID, severity, res.Description, resultLocation(res))
 		highlightCode(res)
+		if len(res.Unresolved) > 0 {
+			_ = tml.Printf("  <white>Unresolved: </white><blue>%s</blue>\n", strings.Join(res.Unresolved, ", "))
+		}
+		if res.Status == result.Ignored {
+			_ = tml.Printf("  <white>Ignored:    </white><blue>%s</blue>\n", ignoreDescription(res))
+		}
 		_ = tml.Printf("  <white>Impact:     </white><blue>%s</blue>\n", res.Impact)
 		_ = tml.Printf("  <white>Resolution: </white><blue>%s</blue>\n", res.Resolution)
 		for _, link := range res.Links {
@@ -87,12 +108,29 @@ func FormatDefault(_ io.Writer, results []result.Result, _ string, options ...Fo
 		printStatistics()
 	}
 
-	terminal.PrintErrorf("\n%d potential problems detected.\n\n", len(results)-countPassedResults(results))
+	terminal.PrintErrorf("\n%d potential problems detected.\n\n", countProblems(results))
+	if unresolved := countStatus(results, result.Unresolved); unresolved > 0 {
+		_ = tml.Printf("<white>%d checks could not be made, as the values they depend on are not known.</white>\n\n", unresolved)
+	}
+	if baselined := countStatus(results, result.Baselined); baselined > 0 {
+		_ = tml.Printf("<white>%d findings are accepted by the baseline.</white>\n\n", baselined)
+	}
 
 	return nil
 
 }
 
+// printDiagnostics lists any problems which may have prevented part of the configuration from being scanned
+func printDiagnostics(diagnostics diagnostic.Diagnostics) {
+	if len(diagnostics) == 0 {
+		return
+	}
+	_ = tml.Printf("\n  <yellow>%d diagnostics reported while reading the configuration, the scan may be incomplete</yellow>\n  ------------------------------------------\n", len(diagnostics))
+	for _, diag := range diagnostics {
+		_ = tml.Printf("  <yellow>[%s]</yellow> %s\n", diag.Severity, diag)
+	}
+}
+
 func printStatistics() {
 	metrics.Add(metrics.FilesLoaded, parser.CountFiles())
 
@@ -1,8 +1,10 @@
 package formatters
 
 import (
+	"fmt"
 	"io"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 )
 
@@ -13,5 +15,81 @@ const (
 	IncludePassed
 )
 
-// Formatter formats scan results into a specific format
-type Formatter func(w io.Writer, results []result.Result, baseDir string, options ...FormatterOption) error
+// Formatter formats scan results into a specific format, along with any diagnostics raised while reading the
+// configuration. Formatters which cannot represent diagnostics ignore them.
+type Formatter func(w io.Writer, results []result.Result, diagnostics diagnostic.Diagnostics, baseDir string, options ...FormatterOption) error
+
+// hasFileLocation returns false for results which do not come from a file, such as those found in a state file
+func hasFileLocation(res result.Result) bool {
+	return res.Range.Filename != ""
+}
+
+// countProblems returns the number of failed checks, which excludes passed, ignored and baselined checks, and checks
+// which could not be made
+func countProblems(results []result.Result) int {
+	problems := 0
+	for _, res := range results {
+		switch res.Status {
+		case result.Passed, result.Unresolved, result.Ignored, result.Baselined:
+			continue
+		}
+		problems++
+	}
+	return problems
+}
+
+// countStatus returns the number of results with the given status
+func countStatus(results []result.Result, status result.Status) int {
+	count := 0
+	for _, res := range results {
+		if res.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// baselinedLast orders the results so that findings accepted by a baseline come after all other results, so that new
+// findings are listed first and the baselined findings can be shown separately
+func baselinedLast(results []result.Result) []result.Result {
+	ordered := make([]result.Result, 0, len(results))
+	for _, res := range results {
+		if res.Status != result.Baselined {
+			ordered = append(ordered, res)
+		}
+	}
+	for _, res := range results {
+		if res.Status == result.Baselined {
+			ordered = append(ordered, res)
+		}
+	}
+	return ordered
+}
+
+// ignoreDescription describes why an ignored result was suppressed, and until when
+func ignoreDescription(res result.Result) string {
+	if res.Excluded {
+		return "check excluded from the scan"
+	}
+	description := res.IgnoreJustification
+	if description == "" {
+		description = "no justification given"
+	}
+	if res.IgnoreExpiry != "" {
+		description = fmt.Sprintf("%s (until %s)", description, res.IgnoreExpiry)
+	}
+	return description
+}
+
+// resultLocation describes where a result was found: the range of code, or the resource address if there is none, along
+// with the root module it was found in when several were scanned
+func resultLocation(res result.Result) string {
+	location := res.Range.String()
+	if !hasFileLocation(res) {
+		location = res.Address
+	}
+	if res.Root != "" {
+		location = fmt.Sprintf("%s (root module %s)", location, res.Root)
+	}
+	return location
+}
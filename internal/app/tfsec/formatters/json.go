@@ -4,16 +4,18 @@ import (
 	"encoding/json"
 	"io"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 )
 
 type JSONOutput struct {
-	Results []result.Result `json:"results"`
+	Results     []result.Result        `json:"results"`
+	Diagnostics diagnostic.Diagnostics `json:"diagnostics,omitempty"`
 }
 
-func FormatJSON(w io.Writer, results []result.Result, _ string, options ...FormatterOption) error {
+func FormatJSON(w io.Writer, results []result.Result, diagnostics diagnostic.Diagnostics, _ string, options ...FormatterOption) error {
 	jsonWriter := json.NewEncoder(w)
 	jsonWriter.SetIndent("", "\t")
 
-	return jsonWriter.Encode(JSONOutput{results})
+	return jsonWriter.Encode(JSONOutput{results, diagnostics})
 }
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 )
 
@@ -30,6 +31,12 @@ type JUnitTestCase struct {
 	Name      string        `xml:"name,attr"`
 	Time      string        `xml:"time,attr"`
 	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+// JUnitSkipped is used for checks which could not be made, as a value they depend on is not known
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
 }
 
 // JUnitFailure contains data related to a failed test.
@@ -39,21 +46,22 @@ type JUnitFailure struct {
 	Contents string `xml:",chardata"`
 }
 
-func FormatJUnit(w io.Writer, results []result.Result, _ string, options ...FormatterOption) error {
+func FormatJUnit(w io.Writer, results []result.Result, _ diagnostic.Diagnostics, _ string, options ...FormatterOption) error {
 
 	output := JUnitTestSuite{
 		Name:     "tfsec",
-		Failures: fmt.Sprintf("%d", len(results)-countPassedResults(results)),
+		Failures: fmt.Sprintf("%d", countProblems(results)),
 		Tests:    fmt.Sprintf("%d", len(results)),
 	}
 
 	for _, result := range results {
 		output.TestCases = append(output.TestCases,
 			JUnitTestCase{
-				Classname: result.Range.Filename,
+				Classname: junitClassname(result),
 				Name:      fmt.Sprintf("[%s][%s] - %s", result.RuleID, result.Severity, result.Description),
 				Time:      "0",
 				Failure:   buildFailure(result),
+				Skipped:   buildSkipped(result),
 			},
 		)
 	}
@@ -68,6 +76,13 @@ func FormatJUnit(w io.Writer, results []result.Result, _ string, options ...Form
 	return xmlEncoder.Encode(output)
 }
 
+func junitClassname(res result.Result) string {
+	if !hasFileLocation(res) {
+		return res.Address
+	}
+	return res.Range.Filename
+}
+
 // highlight the lines of code which caused a problem, if available
 func highlightCodeJunit(result result.Result) string {
 
@@ -105,8 +120,26 @@ func highlightCodeJunit(result result.Result) string {
 	return output
 }
 
+func buildSkipped(res result.Result) *JUnitSkipped {
+	switch res.Status {
+	case result.Unresolved:
+		return &JUnitSkipped{
+			Message: fmt.Sprintf("%s (unresolved: %s)", res.Description, strings.Join(res.Unresolved, ", ")),
+		}
+	case result.Ignored:
+		return &JUnitSkipped{
+			Message: fmt.Sprintf("%s (ignored: %s)", res.Description, ignoreDescription(res)),
+		}
+	case result.Baselined:
+		return &JUnitSkipped{
+			Message: fmt.Sprintf("%s (accepted by the baseline)", res.Description),
+		}
+	}
+	return nil
+}
+
 func buildFailure(res result.Result) *JUnitFailure {
-	if res.Passed() {
+	if res.Passed() || res.Status == result.Unresolved || res.Status == result.Ignored || res.Status == result.Baselined {
 		return nil
 	}
 
@@ -118,7 +151,7 @@ func buildFailure(res result.Result) *JUnitFailure {
 	return &JUnitFailure{
 		Message: res.Description,
 		Contents: fmt.Sprintf("%s\n%s\n%s",
-			res.Range.String(),
+			resultLocation(res),
 			highlightCodeJunit(res),
 			link,
 		),
@@ -1,18 +1,20 @@
 package formatters
 
 import (
+	"encoding/json"
 	"io"
 	"path/filepath"
 	"strings"
 
 	"github.com/tfsec/tfsec/pkg/severity"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 
 	"github.com/owenrumney/go-sarif/sarif"
 )
 
-func FormatSarif(w io.Writer, results []result.Result, baseDir string, _ ...FormatterOption) error {
+func FormatSarif(w io.Writer, results []result.Result, diagnostics diagnostic.Diagnostics, baseDir string, _ ...FormatterOption) error {
 	report, err := sarif.New(sarif.Version210)
 	if err != nil {
 		return err
@@ -35,28 +37,135 @@ func FormatSarif(w io.Writer, results []result.Result, baseDir string, _ ...Form
 			WithDescription(res.RuleSummary).
 			WithHelp(link)
 
-		relativePath, err := filepath.Rel(baseDir, res.Range.Filename)
-		if err != nil {
-			return err
-		}
-
 		message := sarif.NewTextMessage(res.Description)
-		region := sarif.NewSimpleRegion(res.Range.StartLine, res.Range.EndLine)
 		level := strings.ToLower(string(res.Severity))
 		if res.Severity == severity.Info {
 			level = "note"
 		}
 
-		location := sarif.NewPhysicalLocation().
-			WithArtifactLocation(sarif.NewSimpleArtifactLocation(relativePath)).
-			WithRegion(region)
+		location := sarif.NewLocation()
+		if hasFileLocation(res) {
+			relativePath, err := filepath.Rel(baseDir, res.Range.Filename)
+			if err != nil {
+				return err
+			}
+			location.WithPhysicalLocation(sarif.NewPhysicalLocation().
+				WithArtifactLocation(sarif.NewSimpleArtifactLocation(relativePath)).
+				WithRegion(sarif.NewSimpleRegion(res.Range.StartLine, res.Range.EndLine)))
+		} else {
+			// results from state files have no source location, only the address of the resource
+			location.LogicalLocations = append(location.LogicalLocations, sarif.NewLogicalLocation().
+				WithFullyQualifiedName(res.Address).
+				WithKind("resource"))
+		}
 
 		ruleResult := run.AddResult(rule.ID)
 
 		ruleResult.WithMessage(message).
 			WithLevel(level).
-			WithLocation(sarif.NewLocation().WithPhysicalLocation(location))
+			WithLocation(location)
+		properties := sarif.Properties{}
+		if res.Root != "" {
+			properties["root"] = res.Root
+		}
+		if res.Status == result.Unresolved {
+			// an open result is one which could not be determined to pass or fail, and must have no level
+			kind := "open"
+			ruleResult.Kind = &kind
+			ruleResult.WithLevel("none")
+			properties["unresolvedReferences"] = res.Unresolved
+		}
+		if res.Status == result.Baselined {
+			// the finding was present when the baseline was written
+			ruleResult.WithBaselineState("unchanged")
+		}
+		if res.Status == result.Ignored {
+			suppression := sarif.NewSuppression("inSource").WithStatus("accepted")
+			if res.Excluded {
+				// the check was excluded on the command line or in the config, rather than by a comment in the source
+				suppression = sarif.NewSuppression("external").WithStatus("accepted").WithJustifcation("Check excluded from the scan")
+			} else if res.IgnoreJustification != "" {
+				suppression.WithJustifcation(res.IgnoreJustification)
+			}
+			ruleResult.WithSuppression(suppression)
+			if res.IgnoreExpiry != "" {
+				properties["ignoreExpiry"] = res.IgnoreExpiry
+			}
+		}
+		if len(properties) > 0 {
+			ruleResult.Properties = properties
+		}
 	}
 
-	return report.PrettyWrite(w)
+	return writeSarifReport(w, report, run, diagnostics, baseDir)
+}
+
+// The sarif library does not support tool execution notifications, which is how sarif reports problems encountered by
+// the tool itself, so the report is extended to include them in the invocation of the run.
+type sarifReport struct {
+	*sarif.Report
+	Runs []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	*sarif.Run
+	Invocations []*sarifInvocation `json:"invocations"`
+}
+
+type sarifInvocation struct {
+	*sarif.Invocation
+	ToolExecutionNotifications []*sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level      string            `json:"level"`
+	Message    *sarif.Message    `json:"message"`
+	Locations  []*sarif.Location `json:"locations,omitempty"`
+	Properties sarif.Properties  `json:"properties,omitempty"`
+}
+
+func writeSarifReport(w io.Writer, report *sarif.Report, run *sarif.Run, diagnostics diagnostic.Diagnostics, baseDir string) error {
+
+	invocation := &sarifInvocation{
+		Invocation: &sarif.Invocation{
+			ExecutionSuccessful: true,
+		},
+	}
+	for _, diag := range diagnostics {
+		notification := &sarifNotification{
+			Level:   strings.ToLower(string(diag.Severity)),
+			Message: sarif.NewTextMessage(diag.Message),
+			Properties: sarif.Properties{
+				"phase": diag.Phase,
+			},
+		}
+		if diag.Range.Filename != "" {
+			relativePath, err := filepath.Rel(baseDir, diag.Range.Filename)
+			if err != nil {
+				return err
+			}
+			physicalLocation := sarif.NewPhysicalLocation().
+				WithArtifactLocation(sarif.NewSimpleArtifactLocation(relativePath))
+			if diag.Range.StartLine > 0 {
+				physicalLocation.WithRegion(sarif.NewSimpleRegion(diag.Range.StartLine, diag.Range.EndLine))
+			}
+			notification.Locations = append(notification.Locations, sarif.NewLocation().WithPhysicalLocation(physicalLocation))
+		}
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, notification)
+	}
+
+	output, err := json.MarshalIndent(sarifReport{
+		Report: report,
+		Runs: []*sarifRun{
+			{
+				Run:         run,
+				Invocations: []*sarifInvocation{invocation},
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(output)
+	return err
 }
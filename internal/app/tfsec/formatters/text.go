@@ -8,10 +8,18 @@ import (
 
 	"github.com/tfsec/tfsec/pkg/severity"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 )
 
-func FormatText(_ io.Writer, results []result.Result, _ string, options ...FormatterOption) error {
+func FormatText(_ io.Writer, results []result.Result, diagnostics diagnostic.Diagnostics, _ string, options ...FormatterOption) error {
+
+	if len(diagnostics) > 0 {
+		fmt.Printf("\n%d diagnostics reported while reading the configuration, the scan may be incomplete:\n\n", len(diagnostics))
+		for _, diag := range diagnostics {
+			fmt.Printf("  [%s] %s\n", diag.Severity, diag)
+		}
+	}
 
 	if len(results) == 0 || len(results) == countPassedResults(results) {
 		fmt.Print("\nNo problems detected!\n")
@@ -28,8 +36,18 @@ func FormatText(_ io.Writer, results []result.Result, _ string, options ...Forma
 
 	var sev string
 
-	fmt.Printf("\n%d potential problems detected:\n\n", len(results)-countPassedResults(results))
+	fmt.Printf("\n%d potential problems detected:\n\n", countProblems(results))
+	if unresolved := countStatus(results, result.Unresolved); unresolved > 0 {
+		fmt.Printf("%d checks could not be made, as the values they depend on are not known.\n\n", unresolved)
+	}
+	if baselined := countStatus(results, result.Baselined); baselined > 0 {
+		fmt.Printf("%d findings are accepted by the baseline.\n\n", baselined)
+	}
+	results = baselinedLast(results)
 	for i, res := range results {
+		if res.Status == result.Baselined && (i == 0 || results[i-1].Status != result.Baselined) {
+			fmt.Printf("The following findings are accepted by the baseline\n\n")
+		}
 
 		var link string
 		if len(res.Links) > 0 {
@@ -40,6 +58,12 @@ func FormatText(_ io.Writer, results []result.Result, _ string, options ...Forma
 
 		if includePassedChecks && res.Passed() {
 			sev = "PASSED"
+		} else if res.Status == result.Unresolved {
+			sev = "UNRESOLVED"
+		} else if res.Status == result.Ignored {
+			sev = "IGNORED"
+		} else if res.Status == result.Baselined {
+			sev = "BASELINED"
 		} else {
 			switch res.Severity {
 			case severity.Error:
@@ -55,8 +79,14 @@ func FormatText(_ io.Writer, results []result.Result, _ string, options ...Forma
   [%s][%s] %s
   %s
 
-`, res.RuleID, sev, res.Description, res.Range.String())
+`, res.RuleID, sev, res.Description, resultLocation(res))
 		outputCode(res)
+		if len(res.Unresolved) > 0 {
+			fmt.Printf("  Unresolved: %s\n", strings.Join(res.Unresolved, ", "))
+		}
+		if res.Status == result.Ignored {
+			fmt.Printf("  Ignored: %s\n", ignoreDescription(res))
+		}
 		fmt.Printf("  %s\n\n", link)
 	}
 
@@ -0,0 +1,71 @@
+package gitdiff
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+)
+
+// ChangedFiles returns the absolute paths of the files in the git repository containing dir which have changed since
+// the given ref. Changes are compared from the point the current branch diverged from the ref, as a pull request would
+// be, and include uncommitted and untracked files. Only the local repository is read, so the ref must already have been
+// fetched.
+func ChangedFiles(dir string, ref string) ([]string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required to find the files changed since '%s', but it could not be found on the PATH", ref)
+	}
+
+	root, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the git repository for %s: %w", dir, err)
+	}
+	root = strings.TrimSpace(root)
+
+	if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("could not find git ref '%s': %w", ref, err)
+	}
+	base, err := runGit(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		// without a common ancestor, such as in a shallow clone, the ref itself is compared
+		debug.Log("Could not find the merge base of '%s' and HEAD, comparing against '%s' directly: %s", ref, ref, err)
+		base = ref
+	}
+	base = strings.TrimSpace(base)
+
+	changed, err := runGit(dir, "diff", "--name-only", "-z", base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files changed since '%s': %w", ref, err)
+	}
+	untracked, err := runGit(root, "ls-files", "--others", "--exclude-standard", "--full-name", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	var paths []string
+	for _, name := range strings.Split(changed+untracked, "\x00") {
+		if name == "" {
+			continue
+		}
+		paths = append(paths, filepath.Join(root, filepath.FromSlash(name)))
+	}
+	debug.Log("Found %d files changed since '%s'", len(paths), ref)
+	return paths, nil
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
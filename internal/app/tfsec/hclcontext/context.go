@@ -1,7 +1,6 @@
 package hclcontext
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/block"
@@ -37,19 +36,69 @@ func (c *Context) GetDatasByType(t string) block.Blocks {
 	return results
 }
 
-func (c *Context) GetProviderBlocksByProvider(providerName string, alias string) block.Blocks {
-	var results block.Blocks
-	for _, block := range c.blocks {
-		if block.Type() == "provider" && len(block.Labels()) > 0 && block.TypeLabel() == providerName {
+// GetProviderConfig returns the provider block which configures a resource or data block. The provider is followed
+// through its alias and through the providers passed to each module containing the block, in the same way terraform
+// resolves it, so provider settings such as default_tags or region apply to resources inside modules. nil is returned
+// if there is no configuration for the provider, in which case terraform uses an empty default configuration.
+func (c *Context) GetProviderConfig(b *block.Block) *block.Block {
+	name, alias := b.ProviderReference()
+	module := b.ModuleBlock()
+	for {
+		if provider := c.getProviderInModule(module, name, alias); provider != nil {
+			return provider
+		}
+		if module == nil {
+			return nil
+		}
+		providers, passed := module.ModuleProviders()
+		if !passed {
+			// default configurations are inherited from the calling module, but aliased configurations must be passed
 			if alias != "" {
-				if block.HasChild("alias") && block.GetAttribute("alias").Equals(strings.Replace(alias, fmt.Sprintf("%s.", providerName), "", -1)) {
-					results = append(results, block)
-
-				}
-			} else if block.MissingChild("alias") {
-				results = append(results, block)
+				return nil
 			}
+			module = module.ModuleBlock()
+			continue
 		}
+		parentAddress, exists := providers[providerAddress(name, alias)]
+		if !exists {
+			return nil
+		}
+		name, alias = parentAddress, ""
+		if parts := strings.SplitN(parentAddress, ".", 2); len(parts) == 2 {
+			name, alias = parts[0], parts[1]
+		}
+		module = module.ModuleBlock()
 	}
-	return results
+}
+
+// getProviderInModule returns the provider block with the given name and alias defined in the module called by the given
+// module block, or in the root module if the module block is nil
+func (c *Context) getProviderInModule(module *block.Block, name string, alias string) *block.Block {
+	for _, provider := range c.blocks {
+		if provider.Type() != "provider" || provider.TypeLabel() != name || !sameModule(provider.ModuleBlock(), module) {
+			continue
+		}
+		if alias == "" && provider.MissingChild("alias") {
+			return provider
+		}
+		if alias != "" && provider.HasChild("alias") && provider.GetAttribute("alias").Equals(alias) {
+			return provider
+		}
+	}
+	return nil
+}
+
+// sameModule returns true if both module blocks refer to the same module instance
+func sameModule(a *block.Block, b *block.Block) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a == b || a.Address() == b.Address()
+}
+
+func providerAddress(name string, alias string) string {
+	if alias == "" {
+		return name
+	}
+	return name + "." + alias
 }
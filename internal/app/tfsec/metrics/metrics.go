@@ -1,6 +1,12 @@
 package metrics
 
-import "time"
+import (
+	"sync"
+	"time"
+)
+
+// metrics are recorded from multiple goroutines while parsing, so access to them is guarded by a mutex
+var mutex sync.Mutex
 
 var recordedTimes []*Timer
 
@@ -28,6 +34,8 @@ func Start(op Operation) *Timer {
 
 func (t *Timer) Stop() {
 	t.duration = time.Now().Sub(t.started)
+	mutex.Lock()
+	defer mutex.Unlock()
 	recordedTimes = append(recordedTimes, t)
 }
 
@@ -45,10 +53,14 @@ const (
 var counts = map[Count]int{}
 
 func Add(c Count, delta int) {
+	mutex.Lock()
+	defer mutex.Unlock()
 	counts[c] += delta
 }
 
 func TimerSummary() map[Operation]time.Duration {
+	mutex.Lock()
+	defer mutex.Unlock()
 
 	times := make(map[Operation]time.Duration)
 	for _, recorded := range recordedTimes {
@@ -61,5 +73,11 @@ func TimerSummary() map[Operation]time.Duration {
 }
 
 func CountSummary() map[Count]int {
-	return counts
+	mutex.Lock()
+	defer mutex.Unlock()
+	summary := make(map[Count]int, len(counts))
+	for c, count := range counts {
+		summary[c] = count
+	}
+	return summary
 }
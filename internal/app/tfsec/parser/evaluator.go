@@ -1,7 +1,9 @@
 package parser
 
 import (
-	"reflect"
+	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/block"
 
@@ -10,9 +12,12 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
 	"github.com/zclconf/go-cty/cty"
-)
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
 
-const maxContextIterations = 32
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+	"github.com/tfsec/tfsec/pkg/severity"
+)
 
 type visitedModule struct {
 	name string
@@ -26,8 +31,18 @@ type Evaluator struct {
 	visitedModules  []*visitedModule
 	inputVars       map[string]cty.Value
 	moduleMetadata  *ModulesMetadata
+	moduleResolver  ModuleResolver
 	projectRootPath string // root of the current scan
+	modulePath      string // directory of the module being evaluated
+	workspace       string
+	parallelism     int
 	stopOnHCLError  bool
+	values          map[string]map[string]cty.Value // values exposed to expressions, by root name and then by name
+	dataValues      map[string]map[string]cty.Value // values of data sources, by type and then by name
+	stale           map[string]bool                 // root names of the values changed since the context was built
+	instances       map[*block.Block]block.Blocks   // instances of each block which has been expanded
+	diagnostics     diagnostic.Diagnostics
+	evalErrors      *block.EvaluationErrors // panics recovered while evaluating the attributes of the project
 }
 
 func NewEvaluator(
@@ -36,9 +51,13 @@ func NewEvaluator(
 	blocks block.Blocks,
 	inputVars map[string]cty.Value,
 	moduleMetadata *ModulesMetadata,
+	moduleResolver ModuleResolver,
 	modules []*ModuleInfo,
 	visitedModules []*visitedModule,
+	parallelism int,
 	stopOnHCLError bool,
+	workspace string,
+	evalErrors *block.EvaluationErrors,
 ) *Evaluator {
 
 	ctx := &hcl.EvalContext{
@@ -46,134 +65,438 @@ func NewEvaluator(
 		Functions: Functions(modulePath),
 	}
 
+	ctx.Variables["path"] = pathValues(projectRootPath, modulePath)
+	ctx.Variables["terraform"] = cty.ObjectVal(map[string]cty.Value{
+		"workspace": cty.StringVal(workspace),
+	})
+
 	for _, b := range blocks {
 		b.AttachEvalContext(ctx)
+		b.AttachEvaluationErrors(evalErrors)
 	}
 
 	return &Evaluator{
 		projectRootPath: projectRootPath,
+		modulePath:      modulePath,
 		ctx:             ctx,
 		blocks:          blocks,
 		inputVars:       inputVars,
 		moduleMetadata:  moduleMetadata,
+		moduleResolver:  moduleResolver,
 		modules:         modules,
 		visitedModules:  visitedModules,
 		stopOnHCLError:  stopOnHCLError,
+		workspace:       workspace,
+		parallelism:     parallelism,
+		values: map[string]map[string]cty.Value{
+			"var":      {},
+			"local":    {},
+			"provider": {},
+			"output":   {},
+		},
+		dataValues: make(map[string]map[string]cty.Value),
+		stale: map[string]bool{
+			"var":      true,
+			"local":    true,
+			"provider": true,
+			"data":     true,
+			"output":   true,
+		},
+		instances:  make(map[*block.Block]block.Blocks),
+		evalErrors: evalErrors,
+	}
+}
+
+// pathValues returns the path object for a module. Paths are absolute, so that they can be used with the file
+// functions regardless of which module the function is called from. As terraform is run from the root module,
+// path.cwd is the root module directory.
+func pathValues(rootPath string, modulePath string) cty.Value {
+	absolute := func(path string) cty.Value {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return cty.StringVal(path)
 	}
+	return cty.ObjectVal(map[string]cty.Value{
+		"module": absolute(modulePath),
+		"root":   absolute(rootPath),
+		"cwd":    absolute(rootPath),
+	})
 }
 
 func (e *Evaluator) SetModuleBasePath(path string) {
 	e.projectRootPath = path
 }
 
-func (e *Evaluator) evaluateStep(i int) {
+// EvaluateAll evaluates the values of the module in dependency order, so that each value is evaluated once the values
+// it references are known. Blocks using count and for_each are expanded into their instances as they are reached, and
+// modules are evaluated with their inputs, so that their outputs are available to the values which reference them.
+func (e *Evaluator) EvaluateAll() (block.Blocks, error) {
 
 	evalTime := metrics.Start(metrics.Evaluation)
-	debug.Log("Starting iteration %d of hclcontext evaluation...", i+1)
+	graph := newDependencyGraph(e.blocks)
+	order, cycles := graph.sort()
+	for _, cycle := range cycles {
+		cycleDiag := cycleDiagnostic(cycle)
+		debug.Log("%s", cycleDiag.Error())
+		e.diagnostics = append(e.diagnostics, diagnostic.FromHCL(diagnostic.Evaluation, hcl.Diagnostics{cycleDiag})...)
+	}
+	evalTime.Stop()
 
-	e.ctx.Variables["var"] = e.getValuesByBlockType("variable")
-	e.ctx.Variables["local"] = e.getValuesByBlockType("locals")
-	e.ctx.Variables["provider"] = e.getValuesByBlockType("provider")
+	for _, node := range order {
+		e.evaluateNode(node)
+	}
+	e.refreshContext(nil)
 
-	resources := e.getValuesByBlockType("resource")
-	for key, resource := range resources.AsValueMap() {
-		e.ctx.Variables[key] = resource
+	var evaluated block.Blocks
+	for _, b := range e.blocks {
+		if instances, expanded := e.instances[b]; expanded {
+			evaluated = append(evaluated, instances...)
+			continue
+		}
+		evaluated = append(evaluated, b)
 	}
+	e.blocks = evaluated
 
-	e.ctx.Variables["data"] = e.getValuesByBlockType("data")
-	e.ctx.Variables["output"] = e.getValuesByBlockType("output")
+	var allBlocks block.Blocks
+	allBlocks = e.blocks
+	for _, module := range e.modules {
+		allBlocks = mergeBlocks(allBlocks, module.Blocks)
+	}
 
-	evalTime.Stop()
+	return allBlocks, nil
+}
 
-	e.evaluateModules()
+// Diagnostics returns any problems found while evaluating the module and the modules it calls
+func (e *Evaluator) Diagnostics() diagnostic.Diagnostics {
+	return e.diagnostics
 }
 
-func (e *Evaluator) evaluateModules() {
+func (e *Evaluator) evaluateNode(node *graphNode) {
 
-	for _, module := range e.modules {
-		if visited := func(module *ModuleInfo) bool {
-			for _, v := range e.visitedModules {
-				if v.name == module.Name && v.path == module.Path {
-					debug.Log("Module [%s:%s] has already been seen", v.name, v.path)
-					return true
-				}
+	e.refreshContext(node.references)
+
+	if node.blockType == "module" {
+		e.evaluateModules(node)
+		return
+	}
+
+	evalTime := metrics.Start(metrics.Evaluation)
+	defer evalTime.Stop()
+
+	switch node.blockType {
+	case "variable":
+		for _, b := range node.blocks {
+			if val, ok := e.readVariable(b); ok {
+				e.setValue("var", node.name, val)
 			}
-			return false
-		}(module); visited {
-			continue
 		}
+	case "locals":
+		if val, ok := e.evaluateExpression(node.attribute.Expr, e.ctx); ok {
+			e.setValue("local", node.name, val)
+		}
+	case "output":
+		for _, b := range node.blocks {
+			if val, ok := e.readOutput(b); ok {
+				e.setValue("output", node.name, val)
+			}
+		}
+	case "provider":
+		for _, b := range node.blocks {
+			e.setValue("provider", node.name, e.readValues(b))
+		}
+	case "resource", "data":
+		// now that count and for_each values are resolved, the blocks can be expanded into their instances
+		instances := e.expandBlocks(node.blocks)
+		if len(instances) == 0 {
+			return
+		}
+		var val cty.Value
+		for _, b := range instances {
+			val = addInstanceValue(val, b, e.readValues(b))
+		}
+		if node.blockType == "data" {
+			e.setDataValue(instances[0].TypeLabel(), node.name, val)
+		} else {
+			e.setValue(instances[0].TypeLabel(), node.name, val)
+		}
+	}
+}
 
-		e.visitedModules = append(e.visitedModules, &visitedModule{module.Name, module.Path})
+// expandBlocks expands any of the blocks which use count or for_each into their instances. The instances replace the
+// original blocks once evaluation is complete.
+func (e *Evaluator) expandBlocks(blocks block.Blocks) block.Blocks {
+	var expanded block.Blocks
+	for _, b := range blocks {
+		instances := e.expandBlockCounts(e.expandBlockForEaches(block.Blocks{b}))
+		e.instances[b] = instances
+		expanded = append(expanded, instances...)
+	}
+	return expanded
+}
 
-		evalTime := metrics.Start(metrics.Evaluation)
-		inputVars := make(map[string]cty.Value)
-		for _, attr := range module.Definition.GetAttributes() {
-			func() {
-				defer func() {
-					if err := recover(); err != nil {
-						return
-					}
-				}()
-				inputVars[attr.Name()] = attr.Value()
-			}()
+// setValue sets the value exposed to expressions as root.name e.g. var.x or aws_s3_bucket.y
+func (e *Evaluator) setValue(root string, name string, val cty.Value) {
+	if val == cty.NilVal {
+		return
+	}
+	values, exists := e.values[root]
+	if !exists {
+		values = make(map[string]cty.Value)
+		e.values[root] = values
+	}
+	values[name] = val
+	e.stale[root] = true
+}
+
+// setDataValue sets the value exposed to expressions as data.dataType.name
+func (e *Evaluator) setDataValue(dataType string, name string, val cty.Value) {
+	if val == cty.NilVal {
+		return
+	}
+	values, exists := e.dataValues[dataType]
+	if !exists {
+		values = make(map[string]cty.Value)
+		e.dataValues[dataType] = values
+	}
+	values[name] = val
+	e.stale["data"] = true
+}
+
+// refreshContext updates the context variables which have changed since they were last built. Rebuilding a context
+// variable copies all of its values, so only the variables needed for the given references are rebuilt, or all of
+// them if no references are given.
+func (e *Evaluator) refreshContext(references []string) {
+	if references == nil {
+		for root := range e.stale {
+			e.refreshVariable(root)
+		}
+		return
+	}
+	for _, address := range references {
+		if root := strings.SplitN(address, ".", 2)[0]; e.stale[root] {
+			e.refreshVariable(root)
+		}
+	}
+}
+
+func (e *Evaluator) refreshVariable(root string) {
+	delete(e.stale, root)
+	if root == "data" {
+		dataTypes := make(map[string]cty.Value, len(e.dataValues))
+		for dataType, values := range e.dataValues {
+			dataTypes[dataType] = cty.ObjectVal(values)
 		}
-		evalTime.Stop()
+		e.ctx.Variables[root] = cty.ObjectVal(dataTypes)
+		return
+	}
+	e.ctx.Variables[root] = cty.ObjectVal(e.values[root])
+}
 
-		childModules := LoadModules(module.Blocks, e.projectRootPath, e.moduleMetadata, e.stopOnHCLError)
-		moduleEvaluator := NewEvaluator(e.projectRootPath, module.Path, module.Blocks, inputVars, e.moduleMetadata, childModules, e.visitedModules, e.stopOnHCLError)
-		e.SetModuleBasePath(e.projectRootPath)
-		b, _ := moduleEvaluator.EvaluateAll()
-		e.blocks = mergeBlocks(e.blocks, b)
+// evaluateModules evaluates each instance of a module block with its input variables, and exposes the outputs of each
+// instance as module.<name>
+func (e *Evaluator) evaluateModules(node *graphNode) {
 
-		evalTime = metrics.Start(metrics.Evaluation)
-		// export module outputs
-		moduleMapRaw := e.ctx.Variables["module"]
-		if moduleMapRaw == cty.NilVal {
-			moduleMapRaw = cty.ObjectVal(make(map[string]cty.Value))
+	evalTime := metrics.Start(metrics.Evaluation)
+	moduleBlocks := e.expandBlocks(node.blocks)
+	evalTime.Stop()
+
+	var modules []*ModuleInfo
+	for _, module := range e.modules {
+		if !definesModule(node, module) {
+			modules = append(modules, module)
+			continue
 		}
-		moduleMap := moduleMapRaw.AsValueMap()
-		if moduleMap == nil {
-			moduleMap = make(map[string]cty.Value)
+		for _, instance := range moduleInstances(module, moduleBlocks) {
+			e.evaluateModule(instance)
+			modules = append(modules, instance)
 		}
-		moduleMap[module.Name] = moduleEvaluator.ExportOutputs()
-		e.ctx.Variables["module"] = cty.ObjectVal(moduleMap)
-		evalTime.Stop()
 	}
+	e.modules = modules
 }
 
-// export module outputs to a parent hclcontext
-func (e *Evaluator) ExportOutputs() cty.Value {
-	return e.ctx.Variables["output"]
+func definesModule(node *graphNode, module *ModuleInfo) bool {
+	for _, b := range node.blocks {
+		if b.HCL() == module.Definition.HCL() {
+			return true
+		}
+	}
+	return false
 }
 
-func (e *Evaluator) EvaluateAll() (block.Blocks, error) {
+// moduleInstances creates a module instance for each instance of an expanded module block. Each instance has its own
+// copy of the module blocks, so they can be evaluated with the input variables for that instance.
+func moduleInstances(module *ModuleInfo, moduleBlocks block.Blocks) []*ModuleInfo {
+	var instances []*ModuleInfo
+	for _, moduleBlock := range moduleBlocks {
+		if moduleBlock.HCL() != module.Definition.HCL() {
+			continue
+		}
+		if moduleBlock == module.Definition {
+			instances = append(instances, module)
+			continue
+		}
+		var instanceBlocks block.Blocks
+		for _, b := range module.Blocks {
+			instanceBlocks = append(instanceBlocks, block.New(b.HCL(), nil, moduleBlock))
+		}
+		instances = append(instances, &ModuleInfo{
+			Name:       module.Name,
+			Path:       module.Path,
+			Definition: moduleBlock,
+			Blocks:     instanceBlocks,
+		})
+	}
+	return instances
+}
+
+func (e *Evaluator) evaluateModule(module *ModuleInfo) {
+
+	// the local name includes the instance key, so each instance of an expanded module is evaluated
+	name := module.Definition.LocalName()
+	for _, v := range e.visitedModules {
+		if v.name == name && v.path == module.Path {
+			debug.Log("Module [%s:%s] has already been seen", v.name, v.path)
+			return
+		}
+	}
+
+	e.visitedModules = append(e.visitedModules, &visitedModule{name, module.Path})
+
+	evalTime := metrics.Start(metrics.Evaluation)
+	inputVars := make(map[string]cty.Value)
+	for _, attr := range module.Definition.GetAttributes() {
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					return
+				}
+			}()
+			inputVars[attr.Name()] = attr.Value()
+		}()
+	}
+	evalTime.Stop()
+
+	childModules, moduleDiagnostics := LoadModules(module.Blocks, e.projectRootPath, module.Path, e.moduleMetadata, e.moduleResolver, e.parallelism, e.stopOnHCLError)
+	moduleEvaluator := NewEvaluator(e.projectRootPath, module.Path, module.Blocks, inputVars, e.moduleMetadata, e.moduleResolver, childModules, e.visitedModules, e.parallelism, e.stopOnHCLError, e.workspace, e.evalErrors)
+	e.SetModuleBasePath(e.projectRootPath)
+	e.diagnostics = append(e.diagnostics, moduleDiagnostics...)
+	// the module evaluator may have expanded blocks, so we keep the evaluated blocks rather than the originals
+	module.Blocks, _ = moduleEvaluator.EvaluateAll()
+	e.diagnostics = append(e.diagnostics, moduleEvaluator.Diagnostics()...)
 
-	var lastContext hcl.EvalContext
+	// export module outputs
+	e.setValue("module", module.Name, addInstanceValue(e.values["module"][module.Name], module.Definition, moduleEvaluator.ExportOutputs()))
+}
 
-	for i := 0; i < maxContextIterations; i++ {
+// export module outputs to a parent hclcontext
+func (e *Evaluator) ExportOutputs() cty.Value {
+	return e.ctx.Variables["output"]
+}
 
-		e.evaluateStep(i)
+// isExpandable returns true for blocks which can use count and for_each
+func isExpandable(b *block.Block) bool {
+	switch b.Type() {
+	case "resource", "data", "module":
+		return true
+	}
+	return false
+}
 
-		// if ctx matches the last evaluation, we can bail, nothing left to resolve
-		if reflect.DeepEqual(lastContext.Variables, e.ctx.Variables) {
-			break
+// expandBlockCounts replaces each resource, data and module block which uses count with an instance per count index.
+// Blocks with a count of zero are removed, and blocks where the count cannot be resolved are left as they are.
+func (e *Evaluator) expandBlockCounts(blocks block.Blocks) block.Blocks {
+	var expanded block.Blocks
+	for _, b := range blocks {
+		countAttr := b.GetAttribute("count")
+		if countAttr == nil || b.IsExpanded() || !isExpandable(b) {
+			expanded = append(expanded, b)
+			continue
+		}
+		count, ok := readCount(countAttr.Value())
+		if !ok {
+			debug.Log("Could not resolve count for %s, block will not be expanded", b.FullName())
+			expanded = append(expanded, b)
+			continue
+		}
+		for i := 0; i < count; i++ {
+			expanded = append(expanded, b.ExpandCount(i))
 		}
+		debug.Log("Expanded %s into %d instance(s)", b.FullName(), count)
+	}
+	return expanded
+}
 
-		if len(e.ctx.Variables) != len(lastContext.Variables) {
-			lastContext.Variables = make(map[string]cty.Value, len(e.ctx.Variables))
+// expandBlockForEaches replaces each resource, data and module block which uses for_each with an instance per key.
+// Blocks with an empty for_each are removed, and blocks where the for_each cannot be resolved are left as they are.
+func (e *Evaluator) expandBlockForEaches(blocks block.Blocks) block.Blocks {
+	var expanded block.Blocks
+	for _, b := range blocks {
+		forEachAttr := b.GetAttribute("for_each")
+		if forEachAttr == nil || b.IsExpanded() || !isExpandable(b) {
+			expanded = append(expanded, b)
+			continue
+		}
+		instances, ok := readForEach(forEachAttr.Value())
+		if !ok {
+			debug.Log("Could not resolve for_each for %s, block will not be expanded", b.FullName())
+			expanded = append(expanded, b)
+			continue
 		}
-		for k, v := range e.ctx.Variables {
-			lastContext.Variables[k] = v
+		for _, instance := range instances {
+			expanded = append(expanded, b.ExpandForEach(instance.key, instance.value))
 		}
+		debug.Log("Expanded %s into %d instance(s)", b.FullName(), len(instances))
 	}
+	return expanded
+}
 
-	var allBlocks block.Blocks
-	allBlocks = e.blocks
-	for _, module := range e.modules {
-		allBlocks = mergeBlocks(allBlocks, module.Blocks)
+type forEachInstance struct {
+	key   cty.Value
+	value cty.Value
+}
+
+// readForEach returns the key/value pairs of a for_each value in key order. As with terraform, maps and objects use
+// their keys and values, whereas sets must contain strings, which are used as both the key and the value.
+func readForEach(val cty.Value) ([]forEachInstance, bool) {
+	if val.IsNull() || !val.IsKnown() || !val.IsWhollyKnown() {
+		return nil, false
+	}
+	var instances []forEachInstance
+	switch {
+	case val.Type().IsMapType() || val.Type().IsObjectType():
+		for it := val.ElementIterator(); it.Next(); {
+			key, value := it.Element()
+			instances = append(instances, forEachInstance{key: key, value: value})
+		}
+	case val.Type().IsSetType():
+		for it := val.ElementIterator(); it.Next(); {
+			_, element := it.Element()
+			key, err := convert.Convert(element, cty.String)
+			if err != nil || key.IsNull() {
+				return nil, false
+			}
+			instances = append(instances, forEachInstance{key: key, value: key})
+		}
+	default:
+		return nil, false
 	}
+	return instances, true
+}
 
-	return allBlocks, nil
+func readCount(val cty.Value) (int, bool) {
+	if val.IsNull() || !val.IsKnown() {
+		return 0, false
+	}
+	numberVal, err := convert.Convert(val, cty.Number)
+	if err != nil {
+		return 0, false
+	}
+	var count int
+	if err := gocty.FromCtyValue(numberVal, &count); err != nil || count < 0 {
+		return 0, false
+	}
+	return count, true
 }
 
 func mergeBlocks(allBlocks block.Blocks, newBlocks block.Blocks) block.Blocks {
@@ -190,107 +513,157 @@ func mergeBlocks(allBlocks block.Blocks, newBlocks block.Blocks) block.Blocks {
 	return allBlocks
 }
 
-// returns true if all evaluations were successful
-func (e *Evaluator) getValuesByBlockType(blockType string) cty.Value {
-
-	blocksOfType := e.blocks.OfType(blockType)
-	values := make(map[string]cty.Value)
-
-	for _, b := range blocksOfType {
-
-		switch b.Type() {
-		case "variable": // variables are special in that their value comes from the "default" attribute
-
-			if b.Label() == "" {
-				continue
-			}
-
-			attributes, _ := b.HCL().Body.JustAttributes()
-			if attributes == nil {
-				continue
-			}
-
-			if override, exists := e.inputVars[b.Label()]; exists {
-				values[b.Label()] = override
-			} else if def, exists := attributes["default"]; exists {
-				values[b.Label()], _ = def.Expr.Value(e.ctx)
-			}
-		case "output":
-
-			if b.Label() == "" {
-				continue
-			}
-
-			attributes, _ := b.HCL().Body.JustAttributes()
-			if attributes == nil {
-				continue
-			}
+// readVariable returns the value of a variable, which is either given as an input or taken from its default. The value
+// is converted to the type of the variable, filling in the defaults of any optional attributes, and is then checked
+// against the validation blocks of the variable.
+func (e *Evaluator) readVariable(b *block.Block) (cty.Value, bool) {
+	attributes, _ := b.HCL().Body.JustAttributes()
+	val, exists := e.inputVars[b.Label()]
+	if !exists {
+		def, hasDefault := attributes["default"]
+		if !hasDefault {
+			return cty.NilVal, false
+		}
+		var ok bool
+		if val, ok = e.evaluateExpression(def.Expr, e.ctx); !ok {
+			return cty.NilVal, false
+		}
+	}
+	if typeAttr, exists := attributes["type"]; exists {
+		val = e.convertVariable(b, typeAttr.Expr, val)
+	}
+	e.validateVariable(b, val)
+	return val, true
+}
 
-			if def, exists := attributes["value"]; exists {
-				func() {
-					defer func() {
-						_ = recover()
-					}()
-					values[b.Label()], _ = def.Expr.Value(e.ctx)
-				}()
-			}
+// convertVariable converts the value of a variable to its type constraint, as terraform does. Values which cannot be
+// converted are reported, and used as they are.
+func (e *Evaluator) convertVariable(b *block.Block, typeExpr hcl.Expression, val cty.Value) cty.Value {
+	ty, defaults, diags := typeConstraint(typeExpr)
+	if diags.HasErrors() {
+		e.diagnostics = append(e.diagnostics, diagnostic.FromHCL(diagnostic.Evaluation, diags)...)
+		return val
+	}
+	converted, err := convert.Convert(val, ty)
+	if err != nil {
+		message := fmt.Sprintf("Invalid value for variable %q: %s", b.Label(), err)
+		e.diagnostics = append(e.diagnostics, diagnostic.New(diagnostic.Evaluation, severity.Error, message, b.Range()))
+		return val
+	}
+	return defaults.apply(converted)
+}
 
-		case "locals":
-			for key, val := range e.readValues(b.HCL()).AsValueMap() {
-				values[key] = val
-			}
-		case "provider", "module":
-			if b.Label() == "" {
-				continue
+// validateVariable reports each validation condition of a variable which its value does not meet. Conditions which
+// cannot be evaluated, such as those depending on unknown values, are assumed to be met.
+func (e *Evaluator) validateVariable(b *block.Block, val cty.Value) {
+	validations := b.GetBlocks("validation")
+	if len(validations) == 0 {
+		return
+	}
+	// conditions may only refer to the variable being validated
+	ctx := e.ctx.NewChild()
+	ctx.Variables = map[string]cty.Value{
+		"var": cty.ObjectVal(map[string]cty.Value{b.Label(): val}),
+	}
+	for _, validation := range validations {
+		attributes, _ := validation.HCL().Body.JustAttributes()
+		condition, exists := attributes["condition"]
+		if !exists {
+			continue
+		}
+		result, ok := e.evaluateExpression(condition.Expr, ctx)
+		if !ok || !result.IsKnown() || result.IsNull() {
+			continue
+		}
+		if result, err := convert.Convert(result, cty.Bool); err != nil || result.True() {
+			continue
+		}
+		message := fmt.Sprintf("Invalid value for variable %q", b.Label())
+		if errorMessage, exists := attributes["error_message"]; exists {
+			if msg, ok := e.evaluateExpression(errorMessage.Expr, ctx); ok && msg.Type() == cty.String && msg.IsKnown() && !msg.IsNull() {
+				message = fmt.Sprintf("%s: %s", message, msg.AsString())
 			}
-			values[b.Label()] = e.readValues(b.HCL())
-		case "resource", "data":
+		}
+		rng := condition.Expr.Range()
+		e.diagnostics = append(e.diagnostics, diagnostic.New(diagnostic.Evaluation, severity.Error, message, block.Range{
+			Filename:  rng.Filename,
+			StartLine: rng.Start.Line,
+			EndLine:   rng.End.Line,
+		}))
+	}
+}
 
-			if len(b.HCL().Labels) < 2 {
-				continue
-			}
+func (e *Evaluator) readOutput(b *block.Block) (cty.Value, bool) {
+	attributes, _ := b.HCL().Body.JustAttributes()
+	if def, exists := attributes["value"]; exists {
+		return e.evaluateExpression(def.Expr, e.ctx)
+	}
+	return cty.NilVal, false
+}
 
-			blockMap, ok := values[b.HCL().Labels[0]]
-			if !ok {
-				values[b.HCL().Labels[0]] = cty.ObjectVal(make(map[string]cty.Value))
-				blockMap = values[b.HCL().Labels[0]]
-			}
+// evaluateExpression returns the value of an expression, or false if evaluation panicked. As the value will be
+// missing, panics are reported as diagnostics.
+func (e *Evaluator) evaluateExpression(expr hcl.Expression, ctx *hcl.EvalContext) (val cty.Value, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			rng := expr.Range()
+			message := fmt.Sprintf("Failed to evaluate expression: %v", err)
+			e.diagnostics = append(e.diagnostics, diagnostic.New(diagnostic.Evaluation, severity.Warning, message, block.Range{
+				Filename:  rng.Filename,
+				StartLine: rng.Start.Line,
+				EndLine:   rng.End.Line,
+			}))
+			val, ok = cty.NilVal, false
+		}
+	}()
+	val, _ = expr.Value(ctx)
+	return val, true
+}
 
-			valueMap := blockMap.AsValueMap()
-			if valueMap == nil {
-				valueMap = make(map[string]cty.Value)
+// addInstanceValue adds the value for an instance of an expanded block to the values of the instances already seen, in
+// the same way terraform exposes them e.g. aws_s3_bucket.x[0] or module.x["key"]. Blocks which have not been expanded
+// simply use the value.
+func addInstanceValue(existing cty.Value, b *block.Block, value cty.Value) cty.Value {
+	if !b.IsExpanded() {
+		return value
+	}
+	key := b.InstanceKey()
+	if key.Type() == cty.String {
+		instances := make(map[string]cty.Value)
+		if !existing.IsNull() && existing.Type().IsObjectType() {
+			for k, v := range existing.AsValueMap() {
+				instances[k] = v
 			}
-
-			valueMap[b.HCL().Labels[1]] = e.readValues(b.HCL())
-			values[b.HCL().Labels[0]] = cty.ObjectVal(valueMap)
 		}
-
+		instances[key.AsString()] = value
+		return cty.ObjectVal(instances)
 	}
-
-	return cty.ObjectVal(values)
-
+	var instances []cty.Value
+	if !existing.IsNull() && existing.Type().IsTupleType() {
+		instances = existing.AsValueSlice()
+	}
+	return cty.TupleVal(append(instances, value))
 }
 
 // returns true if all evaluations were successful
-func (e *Evaluator) readValues(block *hcl.Block) cty.Value {
+func (e *Evaluator) readValues(b *block.Block) cty.Value {
 
 	values := make(map[string]cty.Value)
 
-	attributes, diagnostics := block.Body.JustAttributes()
+	ctx := b.Context()
+	if ctx == nil {
+		ctx = e.ctx
+	}
+
+	attributes, diagnostics := b.HCL().Body.JustAttributes()
 	if diagnostics != nil && diagnostics.HasErrors() {
 		return cty.NilVal
 	}
 
 	for _, attribute := range attributes {
-		func() {
-			defer func() {
-				if err := recover(); err != nil {
-					return
-				}
-			}()
-			val, _ := attribute.Expr.Value(e.ctx)
+		if val, ok := e.evaluateExpression(attribute.Expr, ctx); ok {
 			values[attribute.Name] = val
-		}()
+		}
 	}
 
 	return cty.ObjectVal(values)
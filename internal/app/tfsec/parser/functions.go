@@ -13,9 +13,11 @@ import (
 // Functions returns the set of functions that should be used to when evaluating
 // expressions in the receiving scope.
 func Functions(baseDir string) map[string]function.Function {
-	return map[string]function.Function{
+	fs := map[string]function.Function{
 		"abs":              stdlib.AbsoluteFunc,
 		"abspath":          funcs.AbsPathFunc,
+		"alltrue":          funcs.AllTrueFunc,
+		"anytrue":          funcs.AnyTrueFunc,
 		"basename":         funcs.BasenameFunc,
 		"base64decode":     funcs.Base64DecodeFunc,
 		"base64encode":     funcs.Base64EncodeFunc,
@@ -39,6 +41,7 @@ func Functions(baseDir string) map[string]function.Function {
 		"dirname":          funcs.DirnameFunc,
 		"distinct":         stdlib.DistinctFunc,
 		"element":          stdlib.ElementFunc,
+		"endswith":         endsWithFunc,
 		"chunklist":        stdlib.ChunklistFunc,
 		"file":             funcs.MakeFileFunc(baseDir, false),
 		"fileexists":       funcs.MakeFileExistsFunc(baseDir),
@@ -57,6 +60,7 @@ func Functions(baseDir string) map[string]function.Function {
 		"formatlist":       stdlib.FormatListFunc,
 		"indent":           stdlib.IndentFunc,
 		"index":            funcs.IndexFunc, // stdlib.IndexFunc is not compatible
+		"issensitive":      isSensitiveFunc,
 		"join":             stdlib.JoinFunc,
 		"jsondecode":       stdlib.JSONDecodeFunc,
 		"jsonencode":       stdlib.JSONEncodeFunc,
@@ -72,8 +76,11 @@ func Functions(baseDir string) map[string]function.Function {
 		"md5":              funcs.Md5Func,
 		"merge":            stdlib.MergeFunc,
 		"min":              stdlib.MinFunc,
+		"nonsensitive":     nonsensitiveFunc,
+		"one":              funcs.OneFunc,
 		"parseint":         stdlib.ParseIntFunc,
 		"pathexpand":       funcs.PathExpandFunc,
+		"plantimestamp":    funcs.TimestampFunc,
 		"pow":              stdlib.PowFunc,
 		"range":            stdlib.RangeFunc,
 		"regex":            stdlib.RegexFunc,
@@ -81,6 +88,7 @@ func Functions(baseDir string) map[string]function.Function {
 		"replace":          funcs.ReplaceFunc,
 		"reverse":          stdlib.ReverseListFunc,
 		"rsadecrypt":       funcs.RsaDecryptFunc,
+		"sensitive":        sensitiveFunc,
 		"setintersection":  stdlib.SetIntersectionFunc,
 		"setproduct":       stdlib.SetProductFunc,
 		"setsubtract":      stdlib.SetSubtractFunc,
@@ -92,10 +100,16 @@ func Functions(baseDir string) map[string]function.Function {
 		"slice":            stdlib.SliceFunc,
 		"sort":             stdlib.SortFunc,
 		"split":            stdlib.SplitFunc,
+		"startswith":       startsWithFunc,
+		"strcontains":      strContainsFunc,
 		"strrev":           stdlib.ReverseFunc,
 		"substr":           stdlib.SubstrFunc,
+		"sum":              funcs.SumFunc,
+		"textdecodebase64": funcs.TextDecodeBase64Func,
+		"textencodebase64": funcs.TextEncodeBase64Func,
 		"timestamp":        funcs.TimestampFunc,
 		"timeadd":          stdlib.TimeAddFunc,
+		"timecmp":          timeCmpFunc,
 		"title":            stdlib.TitleFunc,
 		"tostring":         funcs.MakeToFunc(cty.String),
 		"tonumber":         funcs.MakeToFunc(cty.Number),
@@ -119,4 +133,14 @@ func Functions(baseDir string) map[string]function.Function {
 		"zipmap":           stdlib.ZipmapFunc,
 	}
 
+	fs["templatefile"] = funcs.MakeTemplateFileFunc(baseDir, func() map[string]function.Function {
+		// the templatefile function prevents recursive calls to itself by copying this map and overwriting the
+		// templatefile entry
+		return fs
+	})
+	fs["templatestring"] = makeTemplateStringFunc(func() map[string]function.Function {
+		return fs
+	})
+
+	return fs
 }
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// These functions were added in terraform releases newer than the terraform library we depend on, or behave
+// differently when used for scanning, so they are implemented here.
+
+var startsWithFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+		{Name: "prefix", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.BoolVal(strings.HasPrefix(args[0].AsString(), args[1].AsString())), nil
+	},
+})
+
+var endsWithFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+		{Name: "suffix", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.BoolVal(strings.HasSuffix(args[0].AsString(), args[1].AsString())), nil
+	},
+})
+
+var strContainsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "str", Type: cty.String},
+		{Name: "substr", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.BoolVal(strings.Contains(args[0].AsString(), args[1].AsString())), nil
+	},
+})
+
+// timeCmpFunc compares two RFC 3339 timestamps, returning -1, 0 or 1
+var timeCmpFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "timestamp_a", Type: cty.String},
+		{Name: "timestamp_b", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Number),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		a, err := time.Parse(time.RFC3339, args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Number), function.NewArgError(0, err)
+		}
+		b, err := time.Parse(time.RFC3339, args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Number), function.NewArgError(1, err)
+		}
+		switch {
+		case a.Before(b):
+			return cty.NumberIntVal(-1), nil
+		case a.After(b):
+			return cty.NumberIntVal(1), nil
+		}
+		return cty.NumberIntVal(0), nil
+	},
+})
+
+// Sensitivity makes no difference to a scan, and rules expect to be able to read values directly, so sensitive and
+// nonsensitive return their argument unchanged rather than marking it.
+
+var sensitiveFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "value", Type: cty.DynamicPseudoType, AllowUnknown: true, AllowNull: true},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		return args[0].Type(), nil
+	},
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return args[0], nil
+	},
+})
+
+var nonsensitiveFunc = sensitiveFunc
+
+// isSensitiveFunc always returns an unknown value, as sensitivity is not tracked
+var isSensitiveFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "value", Type: cty.DynamicPseudoType, AllowUnknown: true, AllowNull: true},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(_ []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.UnknownVal(cty.Bool), nil
+	},
+})
+
+// makeTemplateStringFunc renders a template held in a string, in the same way templatefile renders a template file
+func makeTemplateStringFunc(funcsCb func() map[string]function.Function) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "template", Type: cty.String},
+			{Name: "vars", Type: cty.DynamicPseudoType},
+		},
+		Type: function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			expr, diags := hclsyntax.ParseTemplate([]byte(args[0].AsString()), "<templatestring>", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				return cty.DynamicVal, function.NewArgError(0, diags)
+			}
+
+			vars := args[1]
+			if varsType := vars.Type(); !(varsType.IsMapType() || varsType.IsObjectType()) {
+				return cty.DynamicVal, function.NewArgErrorf(1, "invalid vars value: must be a map")
+			}
+			if !vars.IsWhollyKnown() {
+				return cty.DynamicVal, nil
+			}
+
+			functions := make(map[string]function.Function)
+			for name, fn := range funcsCb() {
+				if name == "templatestring" || name == "templatefile" {
+					continue
+				}
+				functions[name] = fn
+			}
+
+			val, diags := expr.Value(&hcl.EvalContext{
+				Variables: vars.AsValueMap(),
+				Functions: functions,
+			})
+			if diags.HasErrors() {
+				return cty.DynamicVal, fmt.Errorf("failed to render template: %w", diags)
+			}
+			return val, nil
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// terraformFunctions is the set of built-in functions available in current versions of terraform
+var terraformFunctions = []string{
+	"abs", "abspath", "alltrue", "anytrue", "base64decode", "base64encode", "base64gzip", "base64sha256",
+	"base64sha512", "basename", "bcrypt", "can", "ceil", "chomp", "chunklist", "cidrhost", "cidrnetmask", "cidrsubnet",
+	"cidrsubnets", "coalesce", "coalescelist", "compact", "concat", "contains", "csvdecode", "dirname", "distinct",
+	"element", "endswith", "file", "filebase64", "filebase64sha256", "filebase64sha512", "fileexists", "filemd5",
+	"fileset", "filesha1", "filesha256", "filesha512", "flatten", "floor", "format", "formatdate", "formatlist",
+	"indent", "index", "issensitive", "join", "jsondecode", "jsonencode", "keys", "length", "log", "lookup", "lower",
+	"matchkeys", "max", "md5", "merge", "min", "nonsensitive", "one", "parseint", "pathexpand", "plantimestamp", "pow",
+	"range", "regex", "regexall", "replace", "reverse", "rsadecrypt", "sensitive", "setintersection", "setproduct",
+	"setsubtract", "setunion", "sha1", "sha256", "sha512", "signum", "slice", "sort", "split", "startswith",
+	"strcontains", "strrev", "substr", "sum", "templatefile", "templatestring", "textdecodebase64", "textencodebase64",
+	"timeadd", "timecmp", "timestamp", "title", "tobool", "tolist", "tomap", "tonumber", "toset", "tostring",
+	"transpose", "trim", "trimprefix", "trimspace", "trimsuffix", "try", "upper", "urlencode", "uuid", "uuidv5",
+	"values", "yamldecode", "yamlencode", "zipmap",
+}
+
+func Test_AllTerraformFunctionsAreRegistered(t *testing.T) {
+	functions := Functions(".")
+	for _, name := range terraformFunctions {
+		assert.Contains(t, functions, name, "terraform function '%s' is not registered", name)
+	}
+	// defaults was removed in terraform 1.3, in favour of optional() attributes with default values
+	assert.NotContains(t, functions, "defaults")
+}
+
+func Test_FunctionEvaluation(t *testing.T) {
+
+	path := createTestFile("test.tf", `
+resource "aws_iam_policy" "policy" {
+	policy = templatefile("${path.module}/policy.json.tpl", { bucket = "logs", actions = ["s3:GetObject"] })
+	inline = templatestring("arn:aws:s3:::$${bucket}", { bucket = "logs" })
+	single = one(["only"])
+	total = sum([1, 2, 3])
+	all = alltrue([true, startswith("prod-eu", "prod")])
+	any = anytrue([false, endswith("prod-eu", "us"), strcontains("prod-eu", "-")])
+	secret = nonsensitive(sensitive("hidden"))
+	decoded = textdecodebase64(textencodebase64("hello", "UTF-16LE"), "UTF-16LE")
+	compared = timecmp("2021-01-02T00:00:00Z", "2021-01-01T00:00:00Z")
+}
+`)
+	writeTestFile(t, filepath.Dir(path), "policy.json.tpl", `{"Resource": "arn:aws:s3:::${bucket}/*", "Action": ${jsonencode(actions)}}`)
+
+	blocks, err := New(filepath.Dir(path)).ParseDirectory()
+	require.NoError(t, err)
+
+	policies := blocks.OfType("resource")
+	require.Len(t, policies, 1)
+	policy := policies[0]
+
+	assert.Equal(t, `{"Resource": "arn:aws:s3:::logs/*", "Action": ["s3:GetObject"]}`, policy.GetAttribute("policy").Value().AsString())
+	assert.Equal(t, "arn:aws:s3:::logs", policy.GetAttribute("inline").Value().AsString())
+	assert.Equal(t, "only", policy.GetAttribute("single").Value().AsString())
+	assert.True(t, policy.GetAttribute("total").Value().Equals(cty.NumberIntVal(6)).True())
+	assert.True(t, policy.GetAttribute("all").Value().True())
+	assert.True(t, policy.GetAttribute("any").Value().True())
+	assert.Equal(t, "hidden", policy.GetAttribute("secret").Value().AsString())
+	assert.Equal(t, "hello", policy.GetAttribute("decoded").Value().AsString())
+	assert.True(t, policy.GetAttribute("compared").Value().Equals(cty.NumberIntVal(1)).True())
+}
@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+)
+
+// graphNode is a value which can be referenced from an expression, such as var.x, local.y, aws_s3_bucket.z or
+// module.m. Locals are each a node of their own, as they are referenced individually rather than by block.
+type graphNode struct {
+	address    string
+	blockType  string
+	name       string         // the name the value is exposed as within its namespace
+	blocks     block.Blocks   // blocks defining the value - more than one if the same address is defined twice
+	attribute  *hcl.Attribute // the attribute defining a local
+	references []string       // addresses of the nodes this node depends on
+}
+
+// defRange returns where the node is defined, for use in diagnostics
+func (n *graphNode) defRange() hcl.Range {
+	if n.attribute != nil {
+		return n.attribute.Range
+	}
+	return n.blocks[0].HCL().DefRange
+}
+
+// dependencyGraph holds the references between the values of a module, so that they can be evaluated in dependency
+// order.
+type dependencyGraph struct {
+	nodes []*graphNode
+	index map[string]*graphNode
+}
+
+func newDependencyGraph(blocks block.Blocks) *dependencyGraph {
+	graph := &dependencyGraph{
+		index: make(map[string]*graphNode),
+	}
+	for _, b := range blocks {
+		switch b.Type() {
+		case "variable":
+			if b.Label() == "" {
+				continue
+			}
+			graph.addBlock("var."+b.Label(), b.Type(), b.Label(), b)
+		case "output", "provider", "module":
+			if b.Label() == "" {
+				continue
+			}
+			graph.addBlock(b.Type()+"."+b.Label(), b.Type(), b.Label(), b)
+		case "resource":
+			if len(b.Labels()) < 2 {
+				continue
+			}
+			graph.addBlock(b.TypeLabel()+"."+b.NameLabel(), b.Type(), b.NameLabel(), b)
+		case "data":
+			if len(b.Labels()) < 2 {
+				continue
+			}
+			graph.addBlock("data."+b.TypeLabel()+"."+b.NameLabel(), b.Type(), b.NameLabel(), b)
+		case "locals":
+			attributes, diagnostics := b.HCL().Body.JustAttributes()
+			if diagnostics != nil && diagnostics.HasErrors() {
+				continue
+			}
+			var ordered []*hcl.Attribute
+			for _, attribute := range attributes {
+				ordered = append(ordered, attribute)
+			}
+			sort.Slice(ordered, func(i, j int) bool {
+				return ordered[i].Range.Start.Byte < ordered[j].Range.Start.Byte
+			})
+			for _, attribute := range ordered {
+				node := graph.addBlock("local."+attribute.Name, b.Type(), attribute.Name, b)
+				node.attribute = attribute
+			}
+		}
+	}
+	for _, node := range graph.nodes {
+		node.references = graph.findReferences(node)
+	}
+	return graph
+}
+
+func (g *dependencyGraph) addBlock(address string, blockType string, name string, b *block.Block) *graphNode {
+	if node, exists := g.index[address]; exists {
+		node.blocks = append(node.blocks, b)
+		return node
+	}
+	node := &graphNode{
+		address:   address,
+		blockType: blockType,
+		name:      name,
+		blocks:    block.Blocks{b},
+	}
+	g.nodes = append(g.nodes, node)
+	g.index[address] = node
+	return node
+}
+
+// findReferences returns the addresses of the nodes referenced by the expressions defining the node. References to
+// anything which is not a node, such as path.module or count.index, are ignored.
+func (g *dependencyGraph) findReferences(node *graphNode) []string {
+	var traversals []hcl.Traversal
+	switch node.blockType {
+	case "locals":
+		traversals = node.attribute.Expr.Variables()
+	case "variable":
+		// the validation blocks of a variable refer to the variable itself, so only the default is relevant
+		for _, b := range node.blocks {
+			if def := b.GetAttribute("default"); def != nil {
+				traversals = append(traversals, def.References()...)
+			}
+		}
+	default:
+		for _, b := range node.blocks {
+			traversals = append(traversals, b.References()...)
+		}
+	}
+
+	var references []string
+	seen := make(map[string]bool)
+	for _, traversal := range traversals {
+		address := referenceAddress(traversal)
+		if address == "" || seen[address] {
+			continue
+		}
+		seen[address] = true
+		if _, exists := g.index[address]; exists {
+			references = append(references, address)
+		}
+	}
+	return references
+}
+
+// referenceAddress returns the address of the node a traversal refers to, e.g. local.x for local.x.y[0]
+func referenceAddress(traversal hcl.Traversal) string {
+	if len(traversal) == 0 {
+		return ""
+	}
+	var names []string
+	for _, step := range traversal[1:] {
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+		names = append(names, attr.Name)
+	}
+	root := traversal.RootName()
+	var required int
+	switch root {
+	case "count", "each", "self", "path", "terraform":
+		return ""
+	case "data":
+		required = 2
+	default:
+		required = 1
+	}
+	if len(names) < required {
+		return ""
+	}
+	return strings.Join(append([]string{root}, names[:required]...), ".")
+}
+
+// sort returns the nodes in the order they should be evaluated, where each node comes after the nodes it references.
+// Nodes which reference each other cannot be ordered, so they are returned together, and each such cycle is reported.
+func (g *dependencyGraph) sort() ([]*graphNode, [][]*graphNode) {
+	// Tarjan's algorithm finds the strongly connected components of the graph, in reverse topological order. As our
+	// edges point from a node to its dependencies, that is exactly the order of evaluation.
+	var (
+		order   []*graphNode
+		cycles  [][]*graphNode
+		stack   []*graphNode
+		next    int
+		indices = make(map[*graphNode]int)
+		lowLink = make(map[*graphNode]int)
+		onStack = make(map[*graphNode]bool)
+	)
+
+	var connect func(node *graphNode)
+	connect = func(node *graphNode) {
+		indices[node] = next
+		lowLink[node] = next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		selfReference := false
+		for _, address := range node.references {
+			dependency := g.index[address]
+			if dependency == node {
+				selfReference = true
+			}
+			if _, visited := indices[dependency]; !visited {
+				connect(dependency)
+				if lowLink[dependency] < lowLink[node] {
+					lowLink[node] = lowLink[dependency]
+				}
+			} else if onStack[dependency] && indices[dependency] < lowLink[node] {
+				lowLink[node] = indices[dependency]
+			}
+		}
+
+		if lowLink[node] != indices[node] {
+			return
+		}
+		var component []*graphNode
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component = append(component, member)
+			if member == node {
+				break
+			}
+		}
+		if len(component) > 1 || selfReference {
+			// keep the cycle in source order, so it is reported consistently
+			sort.Slice(component, func(i, j int) bool {
+				return indices[component[i]] < indices[component[j]]
+			})
+			cycles = append(cycles, component)
+		}
+		order = append(order, component...)
+	}
+
+	for _, node := range g.nodes {
+		if _, visited := indices[node]; !visited {
+			connect(node)
+		}
+	}
+	return order, cycles
+}
+
+// cycleDiagnostic describes a set of values which reference each other, and therefore cannot be fully evaluated
+func cycleDiagnostic(cycle []*graphNode) *hcl.Diagnostic {
+	var addresses []string
+	for _, node := range cycle {
+		addresses = append(addresses, node.address)
+	}
+	subject := cycle[0].defRange()
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Cycle in references",
+		Detail:   fmt.Sprintf("The following values reference each other, so they may not be fully evaluated: %s.", strings.Join(addresses, ", ")),
+		Subject:  &subject,
+	}
+}
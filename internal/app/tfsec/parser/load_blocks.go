@@ -2,7 +2,10 @@ package parser
 
 import (
 	"fmt"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
 	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 
 	"github.com/hashicorp/hcl/v2"
 )
@@ -23,3 +26,47 @@ func LoadBlocksFromFile(file *hcl.File) (hcl.Blocks, error) {
 
 	return contents.Blocks, nil
 }
+
+// LoadBlocksFromDirectory reads the blocks from all terraform files in a directory, applying any override files
+func LoadBlocksFromDirectory(dir string, stopOnHCLError bool) (hcl.Blocks, diagnostic.Diagnostics, error) {
+
+	files, diagnostics, err := LoadDirectory(dir, stopOnHCLError)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blocks, blockDiagnostics, err := loadBlocksFromFiles(files, stopOnHCLError)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blocks, append(diagnostics, blockDiagnostics...), nil
+}
+
+// loadBlocksFromFiles reads the blocks from the terraform files of a single directory, applying any override files.
+// Files which could not be read are given as nil, and are skipped.
+func loadBlocksFromFiles(files []*hcl.File, stopOnHCLError bool) (hcl.Blocks, diagnostic.Diagnostics, error) {
+
+	var blocks hcl.Blocks
+	var diagnostics diagnostic.Diagnostics
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		fileBlocks, err := LoadBlocksFromFile(file)
+		if err != nil {
+			if stopOnHCLError {
+				return nil, nil, err
+			}
+			diagnostics = append(diagnostics, diagnostic.FromError(diagnostic.Parse, err)...)
+			continue
+		}
+		if len(fileBlocks) > 0 {
+			debug.Log("Added %d blocks from %s...", len(fileBlocks), fileBlocks[0].DefRange.Filename)
+		}
+		blocks = append(blocks, fileBlocks...)
+	}
+
+	blocks, overrideDiagnostics := applyOverrides(blocks)
+	return blocks, append(diagnostics, overrideDiagnostics...), nil
+}
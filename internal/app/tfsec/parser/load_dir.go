@@ -3,60 +3,144 @@ package parser
 import (
 	"fmt"
 	"io/ioutil"
-	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
-
-	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 )
 
-var knownFiles = make(map[string]struct{})
+var knownFiles = struct {
+	sync.Mutex
+	paths map[string]struct{}
+}{
+	paths: make(map[string]struct{}),
+}
 
 func CountFiles() int {
-	return len(knownFiles)
+	knownFiles.Lock()
+	defer knownFiles.Unlock()
+	return len(knownFiles.paths)
+}
+
+func addKnownFile(path string) {
+	knownFiles.Lock()
+	defer knownFiles.Unlock()
+	knownFiles.paths[path] = struct{}{}
+}
+
+// LoadDirectory parses all terraform files in a directory, in file name order. Unless stopOnHCLError is set, files
+// with errors are reported as diagnostics rather than failing.
+func LoadDirectory(fullPath string, stopOnHCLError bool) ([]*hcl.File, diagnostic.Diagnostics, error) {
+
+	paths, err := terraformFiles(fullPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, diagnostics, err := parseFiles(paths, 1, stopOnHCLError)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []*hcl.File
+	for _, file := range parsed {
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+
+	return files, diagnostics, nil
 }
 
-func LoadDirectory(fullPath string, stopOnHCLError bool) ([]*hcl.File, error) {
+// terraformFiles returns the paths of the terraform files in a directory, in file name order
+func terraformFiles(dir string) ([]string, error) {
 
 	t := metrics.Start(metrics.DiskIO)
 	defer t.Stop()
 
-	hclParser := hclparse.NewParser()
-
-	fileInfos, err := ioutil.ReadDir(fullPath)
+	fileInfos, err := ioutil.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
 
+	var paths []string
 	for _, info := range fileInfos {
-		if info.IsDir() {
+		if info.IsDir() || !isTerraformFile(info.Name()) {
 			continue
 		}
+		paths = append(paths, filepath.Join(dir, info.Name()))
+	}
+	return paths, nil
+}
 
-		if filepath.Ext(info.Name()) != ".tf" {
-			continue
-		}
+// parseFiles reads and parses the given files, with up to parallelism files being parsed at a time. The files are
+// returned in the order given, with nil in place of any file which could not be read.
+func parseFiles(paths []string, parallelism int, stopOnHCLError bool) ([]*hcl.File, diagnostic.Diagnostics, error) {
 
-		path := filepath.Join(fullPath, info.Name())
-		_, diag := hclParser.ParseHCLFile(path)
+	files := make([]*hcl.File, len(paths))
+	fileDiagnostics := make([]diagnostic.Diagnostics, len(paths))
+	err := runInParallel(parallelism, len(paths), func(i int) error {
+		file, diag := parseFile(paths[i])
 		if diag != nil && diag.HasErrors() {
 			if stopOnHCLError {
-				return nil, diag
+				return diag
 			}
-			_, _ = fmt.Fprintf(os.Stderr, "WARNING: HCL error: %s\n", diag)
-			continue
+			fileDiagnostics[i] = diagnostic.FromHCL(diagnostic.Parse, diag)
+		} else {
+			addKnownFile(paths[i])
 		}
+		// files with errors are still partially parsed, so the blocks which could be parsed are kept
+		files[i] = file
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
-		knownFiles[path] = struct{}{}
+	var diagnostics diagnostic.Diagnostics
+	for _, diags := range fileDiagnostics {
+		diagnostics = append(diagnostics, diags...)
 	}
 
-	var files []*hcl.File
-	for _, file := range hclParser.Files() {
-		files = append(files, file)
+	return files, diagnostics, nil
+}
+
+func parseFile(path string) (*hcl.File, hcl.Diagnostics) {
+
+	t := metrics.Start(metrics.DiskIO)
+	src, err := ioutil.ReadFile(path)
+	t.Stop()
+	if err != nil {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read file",
+				Detail:   fmt.Sprintf("The file %q could not be read: %s.", path, err),
+				Subject:  &hcl.Range{Filename: path},
+			},
+		}
 	}
 
-	return files, nil
+	t = metrics.Start(metrics.HCLParse)
+	defer t.Stop()
+
+	if isJSONFile(path) {
+		return json.Parse(src, path)
+	}
+	return hclsyntax.ParseConfig(src, path, hcl.Pos{Line: 1, Column: 1})
+}
+
+// isTerraformFile returns true for terraform configuration files in either native (.tf) or JSON (.tf.json) syntax
+func isTerraformFile(name string) bool {
+	return filepath.Ext(name) == ".tf" || isJSONFile(name)
+}
+
+func isJSONFile(name string) bool {
+	return strings.HasSuffix(name, ".tf.json")
 }
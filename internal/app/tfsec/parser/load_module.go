@@ -2,7 +2,6 @@ package parser
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -12,6 +11,8 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+	"github.com/tfsec/tfsec/pkg/severity"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -22,51 +23,77 @@ type ModuleInfo struct {
 	Blocks     block.Blocks
 }
 
-// LoadModules reads all module blocks and loads the underlying modules, adding blocks to e.moduleBlocks
-func LoadModules(blocks block.Blocks, projectBasePath string, metadata *ModulesMetadata, stopOnHCLError bool) []*ModuleInfo {
-
-	var modules []*ModuleInfo
+// LoadModules reads all module blocks and loads the underlying modules, adding blocks to e.moduleBlocks. Local module
+// sources are relative to modulePath, the directory of the module containing the blocks. Up to parallelism modules are
+// loaded at a time, and the modules are returned in the order of their blocks. Modules which cannot be loaded are
+// reported as diagnostics.
+func LoadModules(blocks block.Blocks, projectBasePath string, modulePath string, metadata *ModulesMetadata, resolver ModuleResolver, parallelism int, stopOnHCLError bool) ([]*ModuleInfo, diagnostic.Diagnostics) {
 
+	var moduleBlocks block.Blocks
 	for _, moduleBlock := range blocks.OfType("module") {
-		if moduleBlock.Label() == "" {
-			continue
+		if moduleBlock.Label() != "" {
+			moduleBlocks = append(moduleBlocks, moduleBlock)
 		}
-		module, err := loadModule(moduleBlock, projectBasePath, metadata, stopOnHCLError)
+	}
+
+	loaded := make([]*ModuleInfo, len(moduleBlocks))
+	moduleDiagnostics := make([]diagnostic.Diagnostics, len(moduleBlocks))
+	_ = runInParallel(parallelism, len(moduleBlocks), func(i int) error {
+		module, diagnostics, err := loadModule(moduleBlocks[i], projectBasePath, modulePath, metadata, resolver, stopOnHCLError)
+		moduleDiagnostics[i] = diagnostics
 		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "WARNING: Failed to load module: %s\n", err)
-			continue
+			message := fmt.Sprintf("Failed to load module: %s", err)
+			moduleDiagnostics[i] = append(moduleDiagnostics[i], diagnostic.New(diagnostic.ModuleLoad, severity.Warning, message, moduleBlocks[i].Range()))
+			return nil
 		}
 		metrics.Add(metrics.ModuleBlocksLoaded, len(module.Blocks))
-		modules = append(modules, module)
+		loaded[i] = module
+		return nil
+	})
+
+	var modules []*ModuleInfo
+	var diagnostics diagnostic.Diagnostics
+	for i, module := range loaded {
+		diagnostics = append(diagnostics, moduleDiagnostics[i]...)
+		if module != nil {
+			modules = append(modules, module)
+		}
 	}
 
-	return modules
+	return modules, diagnostics
 }
 
 // takes in a module "x" {} block and loads resources etc. into e.moduleBlocks - additionally returns variables to add to ["module.x.*"] variables
-func loadModule(b *block.Block, projectBasePath string, metadata *ModulesMetadata, stopOnHCLError bool) (*ModuleInfo, error) {
+func loadModule(b *block.Block, projectBasePath string, parentPath string, metadata *ModulesMetadata, resolver ModuleResolver, stopOnHCLError bool) (*ModuleInfo, diagnostic.Diagnostics, error) {
 
 	if b.Label() == "" {
-		return nil, fmt.Errorf("module without label at %s", b.Range())
+		return nil, nil, fmt.Errorf("module without label at %s", b.Range())
 	}
 
 	evalTime := metrics.Start(metrics.Evaluation)
 
 	var source string
+	var version string
 	attrs, _ := b.HCL().Body.JustAttributes()
 	for _, attr := range attrs {
-		if attr.Name == "source" {
+		switch attr.Name {
+		case "source":
 			sourceVal, _ := attr.Expr.Value(&hcl.EvalContext{})
 			if sourceVal.Type() == cty.String {
 				source = sourceVal.AsString()
 			}
+		case "version":
+			versionVal, _ := attr.Expr.Value(&hcl.EvalContext{})
+			if versionVal.Type() == cty.String {
+				version = versionVal.AsString()
+			}
 		}
 	}
 
 	evalTime.Stop()
 
 	if source == "" {
-		return nil, fmt.Errorf("could not read module source attribute at %s", b.Range().String())
+		return nil, nil, fmt.Errorf("could not read module source attribute at %s", b.Range().String())
 	}
 
 	var modulePath string
@@ -80,20 +107,28 @@ func loadModule(b *block.Block, projectBasePath string, metadata *ModulesMetadat
 			}
 		}
 	}
+	if modulePath == "" && !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") && resolver != nil {
+		// without metadata, remote modules can still be found if they are available in a local module cache
+		resolvedPath, err := resolver.ResolveModule(source, version)
+		if err != nil {
+			return nil, nil, err
+		}
+		modulePath = resolvedPath
+	}
 	if modulePath == "" {
 		// if we have no metadata, we can only support modules available on the local filesystem
 		// users wanting this feature should run a `terraform init` before running tfsec to cache all modules locally
 		if !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") {
-			return nil, fmt.Errorf("missing module with source '%s' -  try to 'terraform init' first", source)
+			return nil, nil, fmt.Errorf("missing module with source '%s' -  try to 'terraform init' first", source)
 		}
 
-		modulePath = reconstructPath(projectBasePath, source)
+		modulePath = reconstructPath(parentPath, source)
 	}
 
 	var blocks block.Blocks
-	err := getModuleBlocks(b, modulePath, &blocks, stopOnHCLError)
+	diagnostics, err := getModuleBlocks(b, modulePath, &blocks, stopOnHCLError)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	debug.Log("Loaded module '%s' (requested at %s)", modulePath, b.Range())
 	metrics.Add(metrics.ModuleLoadCount, 1)
@@ -103,7 +138,7 @@ func loadModule(b *block.Block, projectBasePath string, metadata *ModulesMetadat
 		Path:       modulePath,
 		Definition: b,
 		Blocks:     blocks,
-	}, nil
+	}, diagnostics, nil
 }
 
 // This function takes the relative source path provided by `source` and reconstructs the absolute path
@@ -118,27 +153,13 @@ func reconstructPath(projectBasePath string, source string) string {
 	return filepath.Join(projectBasePath, source)
 }
 
-func getModuleBlocks(b *block.Block, modulePath string, blocks *block.Blocks, stopOnHCLError bool) error {
-	moduleFiles, err := LoadDirectory(modulePath, stopOnHCLError)
+func getModuleBlocks(b *block.Block, modulePath string, blocks *block.Blocks, stopOnHCLError bool) (diagnostic.Diagnostics, error) {
+	moduleBlocks, diagnostics, err := LoadBlocksFromDirectory(modulePath, stopOnHCLError)
 	if err != nil {
-		return fmt.Errorf("failed to load module %s: %w", b.Label(), err)
+		return nil, fmt.Errorf("failed to load module %s: %w", b.Label(), err)
 	}
-
-	for _, file := range moduleFiles {
-		fileBlocks, err := LoadBlocksFromFile(file)
-		if err != nil {
-			if stopOnHCLError {
-				return err
-			}
-			_, _ = fmt.Fprintf(os.Stderr, "WARNING: HCL error: %s\n", err)
-			continue
-		}
-		if len(fileBlocks) > 0 {
-			debug.Log("Added %d blocks from %s...", len(fileBlocks), fileBlocks[0].DefRange.Filename)
-		}
-		for _, fileBlock := range fileBlocks {
-			*blocks = append(*blocks, block.New(fileBlock, nil, b))
-		}
+	for _, moduleBlock := range moduleBlocks {
+		*blocks = append(*blocks, block.New(moduleBlock, nil, b))
 	}
-	return nil
+	return diagnostics, nil
 }
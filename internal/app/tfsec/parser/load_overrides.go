@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+)
+
+// isOverrideFile returns true for files which terraform treats as overrides: override.tf, *_override.tf and their
+// JSON equivalents
+func isOverrideFile(filename string) bool {
+	name := strings.TrimSuffix(filepath.Base(filename), ".json")
+	return name == "override.tf" || strings.HasSuffix(name, "_override.tf")
+}
+
+// applyOverrides merges blocks from override files into the blocks they override, as terraform does. Override files
+// are applied in lexical order once all other blocks in the directory have been loaded. Overrides which do not match
+// anything are reported as warnings.
+func applyOverrides(hclBlocks hcl.Blocks) (hcl.Blocks, diagnostic.Diagnostics) {
+
+	var base hcl.Blocks
+	var overrides hcl.Blocks
+	for _, hclBlock := range hclBlocks {
+		if isOverrideFile(hclBlock.DefRange.Filename) {
+			overrides = append(overrides, hclBlock)
+		} else {
+			base = append(base, hclBlock)
+		}
+	}
+
+	sort.SliceStable(overrides, func(i, j int) bool {
+		if overrides[i].DefRange.Filename != overrides[j].DefRange.Filename {
+			return overrides[i].DefRange.Filename < overrides[j].DefRange.Filename
+		}
+		return overrides[i].DefRange.Start.Byte < overrides[j].DefRange.Start.Byte
+	})
+
+	var diags hcl.Diagnostics
+	for _, override := range overrides {
+		if override.Type == "locals" {
+			diags = append(diags, overrideLocals(base, override)...)
+			continue
+		}
+		var found bool
+		for i, hclBlock := range base {
+			if hclBlock.Type != override.Type || !labelsMatch(hclBlock.Labels, override.Labels) {
+				continue
+			}
+			merged := *hclBlock
+			merged.Body = block.MergeBodies(hclBlock.Body, override.Body)
+			base[i] = &merged
+			found = true
+			debug.Log("Applied override at %s to %s", override.DefRange, hclBlock.DefRange)
+			break
+		}
+		if !found {
+			diags = append(diags, overrideWarning("Override has no matching block to override", override.DefRange))
+		}
+	}
+
+	return base, diagnostic.FromHCL(diagnostic.Parse, diags)
+}
+
+// overrideLocals applies locals from an override file to the locals blocks which originally defined them. Unlike other
+// blocks, locals are overridden value by value, regardless of which locals block they were defined in.
+func overrideLocals(base hcl.Blocks, override *hcl.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	overrideAttributes, _ := override.Body.JustAttributes()
+	for name, attr := range overrideAttributes {
+		var found bool
+		for i, hclBlock := range base {
+			if hclBlock.Type != "locals" {
+				continue
+			}
+			attributes, _ := hclBlock.Body.JustAttributes()
+			if _, exists := attributes[name]; !exists {
+				continue
+			}
+			merged := *hclBlock
+			merged.Body = block.MergeBodies(hclBlock.Body, &attributesBody{
+				attributes:       hcl.Attributes{name: attr},
+				missingItemRange: override.Body.MissingItemRange(),
+			})
+			base[i] = &merged
+			found = true
+			break
+		}
+		if !found {
+			diags = append(diags, overrideWarning(fmt.Sprintf("Override for local '%s' has no matching local to override", name), attr.Range))
+		}
+	}
+	return diags
+}
+
+func overrideWarning(summary string, rng hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  summary,
+		Subject:  &rng,
+	}
+}
+
+func labelsMatch(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// attributesBody is a body containing only the given attributes
+type attributesBody struct {
+	attributes       hcl.Attributes
+	missingItemRange hcl.Range
+}
+
+func (b *attributesBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, _, diags := b.PartialContent(schema)
+	return content, diags
+}
+
+func (b *attributesBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	content := &hcl.BodyContent{
+		Attributes:       make(hcl.Attributes),
+		MissingItemRange: b.missingItemRange,
+	}
+	remain := &attributesBody{
+		attributes:       make(hcl.Attributes),
+		missingItemRange: b.missingItemRange,
+	}
+	for name, attr := range b.attributes {
+		remain.attributes[name] = attr
+	}
+	for _, attrSchema := range schema.Attributes {
+		if attr, exists := b.attributes[attrSchema.Name]; exists {
+			content.Attributes[attrSchema.Name] = attr
+			delete(remain.attributes, attrSchema.Name)
+		}
+	}
+	return content, remain, nil
+}
+
+func (b *attributesBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	return b.attributes, nil
+}
+
+func (b *attributesBody) MissingItemRange() hcl.Range {
+	return b.missingItemRange
+}
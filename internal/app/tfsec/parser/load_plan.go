@@ -0,0 +1,428 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+)
+
+// planFile is the subset of the `terraform show -json` plan representation which we read
+type planFile struct {
+	FormatVersion string `json:"format_version"`
+	PlannedValues struct {
+		RootModule planModule `json:"root_module"`
+	} `json:"planned_values"`
+	ResourceChanges []planResourceChange `json:"resource_changes"`
+	Configuration   struct {
+		RootModule configModule `json:"root_module"`
+	} `json:"configuration"`
+}
+
+type planModule struct {
+	Address      string         `json:"address"`
+	Resources    []planResource `json:"resources"`
+	ChildModules []planModule   `json:"child_modules"`
+}
+
+type planResource struct {
+	Address string          `json:"address"`
+	Mode    string          `json:"mode"`
+	Type    string          `json:"type"`
+	Name    string          `json:"name"`
+	Index   json.RawMessage `json:"index"`
+	Values  json.RawMessage `json:"values"`
+}
+
+type planResourceChange struct {
+	Address string `json:"address"`
+	Change  struct {
+		Actions      []string        `json:"actions"`
+		AfterUnknown json.RawMessage `json:"after_unknown"`
+	} `json:"change"`
+}
+
+type configModule struct {
+	ModuleCalls map[string]configModuleCall `json:"module_calls"`
+}
+
+type configModuleCall struct {
+	Source            string       `json:"source"`
+	VersionConstraint string       `json:"version_constraint"`
+	Module            configModule `json:"module"`
+}
+
+// ParsePlan reads the resources from a plan in the JSON format produced by `terraform show -json`. Every count,
+// for_each, module and variable in a plan is already resolved, so no evaluation is required. Blocks are located in the
+// configuration the plan was created from (the parser's initial path) wherever possible, so results refer to the
+// source code.
+func (parser *Parser) ParsePlan(planPath string) (block.Blocks, error) {
+
+	t := metrics.Start(metrics.DiskIO)
+	data, err := ioutil.ReadFile(planPath)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+
+	var plan planFile
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to read plan %s: %w", planPath, err)
+	}
+	if plan.FormatVersion == "" {
+		return nil, fmt.Errorf("%s is not a terraform plan in JSON format - use `terraform show -json` to create one", planPath)
+	}
+
+	unknowns := make(map[string]json.RawMessage)
+	for _, change := range plan.ResourceChanges {
+		unknowns[change.Address] = change.Change.AfterUnknown
+	}
+
+	modulesMetadata, _ := LoadModuleMetadata(parser.initialPath)
+	locator := newSourceLocator(parser.initialPath, plan.Configuration.RootModule, modulesMetadata, parser.moduleResolver)
+
+	blocks, err := planModuleBlocks(plan.PlannedValues.RootModule, unknowns, locator)
+	if err != nil {
+		return nil, err
+	}
+	parser.diagnostics = locator.diagnostics
+
+	metrics.Add(metrics.BlocksLoaded, len(blocks))
+	return blocks, nil
+}
+
+func planModuleBlocks(module planModule, unknowns map[string]json.RawMessage, locator *sourceLocator) (block.Blocks, error) {
+
+	moduleBlock, err := locator.moduleBlock(module.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks block.Blocks
+	for _, resource := range module.Resources {
+		value, err := decodeJSONValue(resource.Values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values of %s: %w", resource.Address, err)
+		}
+		if raw, exists := unknowns[resource.Address]; exists {
+			unknown, err := decodeJSONValue(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read unknown values of %s: %w", resource.Address, err)
+			}
+			value = withUnknowns(value, unknown)
+		}
+
+		key, err := decodeInstanceKey(resource.Index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index of %s: %w", resource.Address, err)
+		}
+
+		blockType := "resource"
+		if resource.Mode == "data" {
+			blockType = "data"
+		}
+		blocks = append(blocks, instanceBlock(locator.valueBlock(module.Address, blockType, []string{resource.Type, resource.Name}, value), moduleBlock, key))
+	}
+
+	for _, child := range module.ChildModules {
+		childBlocks, err := planModuleBlocks(child, unknowns, locator)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, childBlocks...)
+	}
+
+	return blocks, nil
+}
+
+// instanceBlock creates a block for a single instance of a resource or module with the given count index or for_each
+// key, or cty.NilVal if the resource uses neither
+func instanceBlock(hclBlock *hcl.Block, moduleBlock *block.Block, key cty.Value) *block.Block {
+	b := block.New(hclBlock, nil, moduleBlock)
+	switch {
+	case key == cty.NilVal:
+		return b
+	case key.Type() == cty.Number:
+		index, _ := key.AsBigFloat().Int64()
+		return b.ExpandCount(int(index))
+	default:
+		return b.ExpandForEach(key, cty.DynamicVal)
+	}
+}
+
+func decodeJSONValue(raw json.RawMessage) (cty.Value, error) {
+	if len(raw) == 0 {
+		return cty.NilVal, nil
+	}
+	impliedType, err := ctyjson.ImpliedType(raw)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(raw, impliedType)
+}
+
+func decodeInstanceKey(raw json.RawMessage) (cty.Value, error) {
+	key, err := decodeJSONValue(raw)
+	if err != nil || key == cty.NilVal || key.IsNull() {
+		return cty.NilVal, err
+	}
+	if key.Type() != cty.Number && key.Type() != cty.String {
+		return cty.NilVal, fmt.Errorf("unexpected instance key %s", string(raw))
+	}
+	return key, nil
+}
+
+// withUnknowns marks values as unknown where the corresponding after_unknown value is true
+func withUnknowns(value cty.Value, unknown cty.Value) cty.Value {
+
+	if unknown == cty.NilVal || unknown.IsNull() {
+		return value
+	}
+
+	if unknown.Type() == cty.Bool {
+		if unknown.True() {
+			return cty.DynamicVal
+		}
+		return value
+	}
+
+	if value == cty.NilVal {
+		value = cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	switch {
+	case unknown.Type().IsObjectType():
+		values := make(map[string]cty.Value)
+		if !value.IsNull() && value.Type().IsObjectType() {
+			values = value.AsValueMap()
+		}
+		for name, unknownVal := range unknown.AsValueMap() {
+			existing, exists := values[name]
+			if !exists {
+				existing = cty.NullVal(cty.DynamicPseudoType)
+			}
+			if updated := withUnknowns(existing, unknownVal); exists || !updated.IsNull() {
+				values[name] = updated
+			}
+		}
+		if len(values) == 0 {
+			return value
+		}
+		return cty.ObjectVal(values)
+	case unknown.Type().IsTupleType() && !value.IsNull() && value.Type().IsTupleType():
+		values := value.AsValueSlice()
+		unknownValues := unknown.AsValueSlice()
+		for i := range values {
+			if i < len(unknownValues) {
+				values[i] = withUnknowns(values[i], unknownValues[i])
+			}
+		}
+		if len(values) == 0 {
+			return value
+		}
+		return cty.TupleVal(values)
+	}
+
+	return value
+}
+
+// sourceLocator finds the configuration blocks which correspond to resources and modules in a plan or state
+type sourceLocator struct {
+	rootPath    string
+	rootConfig  configModule
+	metadata    *ModulesMetadata
+	resolver    ModuleResolver
+	dirBlocks   map[string]hcl.Blocks
+	moduleCache map[string]*block.Block
+	diagnostics diagnostic.Diagnostics
+}
+
+func newSourceLocator(rootPath string, rootConfig configModule, metadata *ModulesMetadata, resolver ModuleResolver) *sourceLocator {
+	return &sourceLocator{
+		rootPath:    rootPath,
+		rootConfig:  rootConfig,
+		metadata:    metadata,
+		resolver:    resolver,
+		dirBlocks:   make(map[string]hcl.Blocks),
+		moduleCache: make(map[string]*block.Block),
+	}
+}
+
+type moduleAddressStep struct {
+	name string
+	key  cty.Value
+}
+
+// parseModuleAddress splits a module address such as module.a["x"].module.b[0] into its module calls
+func parseModuleAddress(address string) ([]moduleAddressStep, error) {
+	if address == "" {
+		return nil, nil
+	}
+	traversal, diags := hclsyntax.ParseTraversalAbs([]byte(address), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid module address '%s': %s", address, diags.Error())
+	}
+	var steps []moduleAddressStep
+	for i := 0; i < len(traversal); i++ {
+		var keyword string
+		switch step := traversal[i].(type) {
+		case hcl.TraverseRoot:
+			keyword = step.Name
+		case hcl.TraverseAttr:
+			keyword = step.Name
+		}
+		if keyword != "module" || i+1 >= len(traversal) {
+			return nil, fmt.Errorf("invalid module address '%s'", address)
+		}
+		i++
+		name, ok := traversal[i].(hcl.TraverseAttr)
+		if !ok {
+			return nil, fmt.Errorf("invalid module address '%s'", address)
+		}
+		moduleStep := moduleAddressStep{name: name.Name, key: cty.NilVal}
+		if i+1 < len(traversal) {
+			if index, ok := traversal[i+1].(hcl.TraverseIndex); ok {
+				moduleStep.key = index.Key
+				i++
+			}
+		}
+		steps = append(steps, moduleStep)
+	}
+	return steps, nil
+}
+
+// moduleBlock returns the block representing the module instance at the given address, or nil for the root module
+func (l *sourceLocator) moduleBlock(address string) (*block.Block, error) {
+	if address == "" {
+		return nil, nil
+	}
+	if cached, exists := l.moduleCache[address]; exists {
+		return cached, nil
+	}
+	steps, err := parseModuleAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var moduleBlock *block.Block
+	var path []string
+	for _, step := range steps {
+		parentDir := l.moduleDir(path)
+		path = append(path, step.name)
+		hclBlock := l.findBlock(parentDir, "module", []string{step.name})
+		if hclBlock == nil {
+			hclBlock = &hcl.Block{
+				Type:   "module",
+				Labels: []string{step.name},
+				Body:   hcl.EmptyBody(),
+			}
+		}
+		moduleBlock = instanceBlock(hclBlock, moduleBlock, step.key)
+	}
+
+	l.moduleCache[address] = moduleBlock
+	return moduleBlock, nil
+}
+
+// valueBlock creates a block holding the given values, located at the matching block in the configuration if there is one
+func (l *sourceLocator) valueBlock(moduleAddress string, blockType string, labels []string, value cty.Value) *hcl.Block {
+	var path []string
+	if steps, err := parseModuleAddress(moduleAddress); err == nil {
+		for _, step := range steps {
+			path = append(path, step.name)
+		}
+	}
+
+	var source hcl.Body
+	var defRange, typeRange hcl.Range
+	var labelRanges []hcl.Range
+	if sourceBlock := l.findBlock(l.moduleDir(path), blockType, labels); sourceBlock != nil {
+		source = sourceBlock.Body
+		defRange = sourceBlock.DefRange
+		typeRange = sourceBlock.TypeRange
+		labelRanges = sourceBlock.LabelRanges
+	}
+
+	return &hcl.Block{
+		Type:        blockType,
+		Labels:      labels,
+		Body:        block.NewValueBody(value, source, defRange),
+		DefRange:    defRange,
+		TypeRange:   typeRange,
+		LabelRanges: labelRanges,
+	}
+}
+
+// moduleDir returns the source directory of the module with the given path of module call names, or an empty string
+// if it cannot be found
+func (l *sourceLocator) moduleDir(path []string) string {
+	dir := l.rootPath
+	config := l.rootConfig
+	for i, name := range path {
+		call, exists := config.ModuleCalls[name]
+		if !exists {
+			return ""
+		}
+		config = call.Module
+		dir = l.moduleCallDir(dir, strings.Join(path[:i+1], "."), call)
+		if dir == "" {
+			return ""
+		}
+	}
+	return dir
+}
+
+func (l *sourceLocator) moduleCallDir(parentDir string, key string, call configModuleCall) string {
+	if l.metadata != nil {
+		for _, module := range l.metadata.Modules {
+			if module.Key == key {
+				return filepath.Clean(filepath.Join(l.rootPath, module.Dir))
+			}
+		}
+	}
+	if strings.HasPrefix(call.Source, "./") || strings.HasPrefix(call.Source, "../") {
+		return reconstructPath(parentDir, call.Source)
+	}
+	if l.resolver != nil {
+		dir, err := l.resolver.ResolveModule(call.Source, call.VersionConstraint)
+		if err != nil {
+			debug.Log("Could not resolve module '%s': %s", call.Source, err)
+		}
+		return dir
+	}
+	return ""
+}
+
+func (l *sourceLocator) findBlock(dir string, blockType string, labels []string) *hcl.Block {
+	if dir == "" {
+		return nil
+	}
+	blocks, loaded := l.dirBlocks[dir]
+	if !loaded {
+		var diagnostics diagnostic.Diagnostics
+		var err error
+		blocks, diagnostics, err = LoadBlocksFromDirectory(dir, false)
+		if err != nil {
+			debug.Log("Could not load configuration from %s: %s", dir, err)
+		}
+		l.diagnostics = append(l.diagnostics, diagnostics...)
+		l.dirBlocks[dir] = blocks
+	}
+	for _, hclBlock := range blocks {
+		if hclBlock.Type == blockType && labelsMatch(hclBlock.Labels, labels) {
+			return hclBlock
+		}
+	}
+	return nil
+}
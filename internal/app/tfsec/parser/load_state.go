@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
+)
+
+const supportedStateVersion = 4
+
+type stateFile struct {
+	Version   int             `json:"version"`
+	Resources []stateResource `json:"resources"`
+}
+
+type stateResource struct {
+	Module    string          `json:"module"`
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Instances []stateInstance `json:"instances"`
+}
+
+type stateInstance struct {
+	IndexKey   json.RawMessage `json:"index_key"`
+	Deposed    string          `json:"deposed"`
+	Attributes json.RawMessage `json:"attributes"`
+}
+
+// LoadState reads the resource instances recorded in a terraform state file (version 4, as used since terraform 0.12).
+// This includes resources which were imported or changed outside of terraform, but as the state does not record where
+// resources are defined, the blocks have no source range and can only be identified by their address.
+func LoadState(statePath string) (block.Blocks, error) {
+
+	t := metrics.Start(metrics.DiskIO)
+	data, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to read state %s: %w", statePath, err)
+	}
+	if state.Version != supportedStateVersion {
+		return nil, fmt.Errorf("unsupported state version %d in %s, only version %d is supported", state.Version, statePath, supportedStateVersion)
+	}
+
+	locator := newSourceLocator("", configModule{}, nil, nil)
+
+	var blocks block.Blocks
+	for _, resource := range state.Resources {
+		moduleBlock, err := locator.moduleBlock(resource.Module)
+		if err != nil {
+			return nil, err
+		}
+		blockType := "resource"
+		if resource.Mode == "data" {
+			blockType = "data"
+		}
+		for _, instance := range resource.Instances {
+			if instance.Deposed != "" {
+				// deposed objects are about to be destroyed
+				continue
+			}
+			value, err := decodeJSONValue(instance.Attributes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read attributes of %s.%s: %w", resource.Type, resource.Name, err)
+			}
+			key, err := decodeInstanceKey(instance.IndexKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read index of %s.%s: %w", resource.Type, resource.Name, err)
+			}
+			hclBlock := locator.valueBlock(resource.Module, blockType, []string{resource.Type, resource.Name}, value)
+			blocks = append(blocks, instanceBlock(hclBlock, moduleBlock, key))
+		}
+	}
+
+	metrics.Add(metrics.BlocksLoaded, len(blocks))
+	return blocks, nil
+}
@@ -0,0 +1,400 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+)
+
+const terragruntConfigFile = "terragrunt.hcl"
+
+// terragruntConfig holds the parts of a terragrunt.hcl file which affect how the terraform configuration it deploys is
+// evaluated, after any included configuration has been merged in
+type terragruntConfig struct {
+	path   string
+	source string
+	locals map[string]cty.Value
+	inputs map[string]cty.Value
+}
+
+// terragruntLoader reads the terragrunt configuration of a single unit. Functions such as get_terragrunt_dir() and
+// find_in_parent_folders() always refer to the unit, even when they are used in an included file.
+type terragruntLoader struct {
+	unitDir     string
+	includeDir  string
+	reading     map[string]bool
+	diagnostics hcl.Diagnostics
+}
+
+// hasTerragruntConfig returns true if the directory contains a terragrunt.hcl file
+func hasTerragruntConfig(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, terragruntConfigFile))
+	return err == nil && !info.IsDir()
+}
+
+// isTerragruntUnit returns true if the directory is a terragrunt unit which deploys terraform configuration, because its
+// terragrunt.hcl has a source or it has terraform files of its own. A terragrunt.hcl without either, such as the parent
+// configuration at the root of a live repository, only holds settings for the units below it.
+func isTerragruntUnit(dir string) bool {
+	if !hasTerragruntConfig(dir) {
+		return false
+	}
+	if paths, err := terraformFiles(dir); err == nil && len(paths) > 0 {
+		return true
+	}
+	config, _, err := loadTerragruntConfig(dir)
+	// a configuration which cannot be read is treated as a unit, so that the error is reported
+	return err != nil || config.source != ""
+}
+
+// loadTerragruntConfig reads the terragrunt.hcl file of a unit, following its include and dependency blocks. Values
+// which cannot be evaluated, such as the outputs of a dependency without mock_outputs, are unknown and are reported in
+// the returned diagnostics.
+func loadTerragruntConfig(unitDir string) (*terragruntConfig, hcl.Diagnostics, error) {
+	loader := &terragruntLoader{
+		unitDir:    unitDir,
+		includeDir: unitDir,
+		reading:    make(map[string]bool),
+	}
+	config, err := loader.load(filepath.Join(unitDir, terragruntConfigFile), true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, loader.diagnostics, nil
+}
+
+func (l *terragruntLoader) load(path string, followIncludes bool) (*terragruntConfig, error) {
+
+	if l.reading[path] {
+		return nil, fmt.Errorf("terragrunt configuration %s includes itself", path)
+	}
+	l.reading[path] = true
+	defer delete(l.reading, path)
+
+	file, diags := parseFile(path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("terragrunt configuration %s is not in HCL syntax", path)
+	}
+
+	config := &terragruntConfig{
+		path:   path,
+		locals: make(map[string]cty.Value),
+		inputs: make(map[string]cty.Value),
+	}
+	variables := make(map[string]cty.Value)
+
+	// include paths can only use functions, as they are read before anything else. Terragrunt does not allow nested
+	// includes, so the includes of an included file are ignored.
+	includes := make(map[string]cty.Value)
+	if followIncludes {
+		for _, b := range body.Blocks {
+			if b.Type != "include" {
+				continue
+			}
+			includePath := l.evaluateString(b.Body.Attributes["path"], nil)
+			if includePath == "" {
+				continue
+			}
+			if !filepath.IsAbs(includePath) {
+				includePath = filepath.Join(filepath.Dir(path), includePath)
+			}
+			l.includeDir = filepath.Dir(includePath)
+			debug.Log("Including terragrunt configuration %s in %s", includePath, path)
+			included, err := l.load(includePath, false)
+			if err != nil {
+				return nil, err
+			}
+			config.merge(included)
+			if len(b.Labels) > 0 {
+				includes[b.Labels[0]] = cty.ObjectVal(map[string]cty.Value{
+					"locals": cty.ObjectVal(included.locals),
+					"inputs": cty.ObjectVal(included.inputs),
+				})
+			}
+		}
+	}
+	variables["include"] = cty.ObjectVal(includes)
+
+	l.evaluateLocals(body, variables, config.locals)
+	variables["local"] = cty.ObjectVal(config.locals)
+
+	// the outputs of dependencies are only known once they have been applied, so mock_outputs are used in their place
+	dependencies := make(map[string]cty.Value)
+	for _, b := range body.Blocks {
+		if b.Type != "dependency" || len(b.Labels) == 0 {
+			continue
+		}
+		outputs := cty.DynamicVal
+		if mock, exists := b.Body.Attributes["mock_outputs"]; exists {
+			outputs = l.evaluate(mock, variables)
+		}
+		dependencies[b.Labels[0]] = cty.ObjectVal(map[string]cty.Value{
+			"outputs": outputs,
+		})
+	}
+	variables["dependency"] = cty.ObjectVal(dependencies)
+
+	for _, b := range body.Blocks {
+		if b.Type != "terraform" {
+			continue
+		}
+		if source := l.evaluateString(b.Body.Attributes["source"], variables); source != "" {
+			config.source = source
+		}
+	}
+
+	if attr, exists := body.Attributes["inputs"]; exists {
+		inputs := l.evaluate(attr, variables)
+		if inputs.IsKnown() && !inputs.IsNull() && (inputs.Type().IsObjectType() || inputs.Type().IsMapType()) {
+			for name, value := range inputs.AsValueMap() {
+				config.inputs[name] = value
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// merge applies an included configuration, whose inputs are overridden by the including configuration
+func (c *terragruntConfig) merge(included *terragruntConfig) {
+	if c.source == "" {
+		c.source = included.source
+	}
+	for name, value := range included.inputs {
+		if _, exists := c.inputs[name]; !exists {
+			c.inputs[name] = value
+		}
+	}
+}
+
+// evaluateLocals sets the locals of a configuration, which may reference each other in any order. Each pass evaluates
+// the locals with the values found by the previous one, so a chain of references is resolved one step per pass.
+func (l *terragruntLoader) evaluateLocals(body *hclsyntax.Body, variables map[string]cty.Value, locals map[string]cty.Value) {
+
+	var attributes []*hclsyntax.Attribute
+	for _, b := range body.Blocks {
+		if b.Type != "locals" {
+			continue
+		}
+		for _, attr := range b.Body.Attributes {
+			attributes = append(attributes, attr)
+		}
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].SrcRange.Start.Byte < attributes[j].SrcRange.Start.Byte
+	})
+
+	for pass := 0; pass <= len(attributes); pass++ {
+		variables["local"] = cty.ObjectVal(locals)
+		ctx := l.context(variables)
+		unresolved := false
+		for _, attr := range attributes {
+			value, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() || !value.IsWhollyKnown() {
+				unresolved = true
+				if pass == len(attributes) {
+					l.diagnostics = append(l.diagnostics, diags...)
+				}
+			}
+			locals[attr.Name] = value
+		}
+		if !unresolved {
+			return
+		}
+	}
+}
+
+func (l *terragruntLoader) evaluate(attr *hclsyntax.Attribute, variables map[string]cty.Value) cty.Value {
+	if attr == nil {
+		return cty.NilVal
+	}
+	value, diags := attr.Expr.Value(l.context(variables))
+	l.diagnostics = append(l.diagnostics, diags...)
+	return value
+}
+
+func (l *terragruntLoader) evaluateString(attr *hclsyntax.Attribute, variables map[string]cty.Value) string {
+	value := l.evaluate(attr, variables)
+	if value == cty.NilVal || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+		return ""
+	}
+	return value.AsString()
+}
+
+func (l *terragruntLoader) context(variables map[string]cty.Value) *hcl.EvalContext {
+	functions := Functions(l.unitDir)
+	for name, fn := range l.functions() {
+		functions[name] = fn
+	}
+	return &hcl.EvalContext{
+		Variables: variables,
+		Functions: functions,
+	}
+}
+
+// functions returns the terragrunt built-in functions. Functions which depend on the cloud account the unit is deployed
+// to return unknown values.
+func (l *terragruntLoader) functions() map[string]function.Function {
+	stringFunc := func(fn func() string) function.Function {
+		return function.New(&function.Spec{
+			Type: function.StaticReturnType(cty.String),
+			Impl: func(_ []cty.Value, _ cty.Type) (cty.Value, error) {
+				return cty.StringVal(fn()), nil
+			},
+		})
+	}
+	unknownFunc := function.New(&function.Spec{
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(_ []cty.Value, _ cty.Type) (cty.Value, error) {
+			return cty.UnknownVal(cty.String), nil
+		},
+	})
+	relativePath := func(from string, to string) string {
+		path, err := filepath.Rel(from, to)
+		if err != nil {
+			return "."
+		}
+		return filepath.ToSlash(path)
+	}
+
+	return map[string]function.Function{
+		"find_in_parent_folders": function.New(&function.Spec{
+			VarParam: &function.Parameter{Name: "args", Type: cty.String},
+			Type:     function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+				name := terragruntConfigFile
+				if len(args) > 0 {
+					name = args[0].AsString()
+				}
+				for dir := filepath.Dir(l.unitDir); ; dir = filepath.Dir(dir) {
+					if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+						return cty.StringVal(filepath.Join(dir, name)), nil
+					}
+					if filepath.Dir(dir) == dir {
+						break
+					}
+				}
+				if len(args) > 1 {
+					return args[1], nil
+				}
+				return cty.NilVal, fmt.Errorf("could not find %s in any parent folder of %s", name, l.unitDir)
+			},
+		}),
+		"get_env": function.New(&function.Spec{
+			Params:   []function.Parameter{{Name: "name", Type: cty.String}},
+			VarParam: &function.Parameter{Name: "default", Type: cty.String},
+			Type:     function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+				if value, exists := os.LookupEnv(args[0].AsString()); exists {
+					return cty.StringVal(value), nil
+				}
+				if len(args) > 1 {
+					return args[1], nil
+				}
+				return cty.StringVal(""), nil
+			},
+		}),
+		"read_terragrunt_config": function.New(&function.Spec{
+			Params:   []function.Parameter{{Name: "path", Type: cty.String}},
+			VarParam: &function.Parameter{Name: "default", Type: cty.DynamicPseudoType},
+			Type:     function.StaticReturnType(cty.DynamicPseudoType),
+			Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+				path := args[0].AsString()
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(l.unitDir, path)
+				}
+				config, err := l.load(path, false)
+				if err != nil {
+					if len(args) > 1 {
+						return args[1], nil
+					}
+					return cty.NilVal, err
+				}
+				return cty.ObjectVal(map[string]cty.Value{
+					"locals": cty.ObjectVal(config.locals),
+					"inputs": cty.ObjectVal(config.inputs),
+				}), nil
+			},
+		}),
+		"get_terragrunt_dir":        stringFunc(func() string { return l.unitDir }),
+		"get_parent_terragrunt_dir": stringFunc(func() string { return l.includeDir }),
+		"path_relative_to_include":  stringFunc(func() string { return relativePath(l.includeDir, l.unitDir) }),
+		"path_relative_from_include": stringFunc(func() string {
+			return relativePath(l.unitDir, l.includeDir)
+		}),
+		"get_aws_account_id":              unknownFunc,
+		"get_aws_caller_identity_arn":     unknownFunc,
+		"get_aws_caller_identity_user_id": unknownFunc,
+	}
+}
+
+// resolveTerragruntSource returns the directory containing the terraform configuration a unit deploys. Without a
+// source, terragrunt runs terraform in the unit directory itself. Local sources are relative to the unit, and remote
+// sources are found using the module resolver, as terragrunt would otherwise download them.
+func resolveTerragruntSource(unitDir string, source string, resolver ModuleResolver) (string, error) {
+
+	if source == "" {
+		return unitDir, nil
+	}
+
+	if filepath.IsAbs(source) || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		dir, subdir := splitSubdir(source)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(unitDir, dir)
+		}
+		return filepath.Join(dir, subdir), nil
+	}
+
+	var version string
+	if strings.HasPrefix(source, "tfr://") {
+		// registry modules are given as tfr://<host>/<namespace>/<name>/<provider>?version=<version>, where the host
+		// is left empty for the public registry
+		source = strings.TrimPrefix(source, "tfr://")
+		if query := strings.Index(source, "?"); query > -1 {
+			values, err := url.ParseQuery(source[query+1:])
+			if err != nil {
+				return "", fmt.Errorf("invalid terragrunt source 'tfr://%s': %w", source, err)
+			}
+			version = values.Get("version")
+			source = source[:query]
+		}
+		source = strings.TrimPrefix(source, "/")
+	}
+
+	if resolver == nil {
+		return "", fmt.Errorf("terragrunt source '%s' is not on the local filesystem - use a module cache to resolve it", source)
+	}
+	dir, err := resolver.ResolveModule(source, version)
+	if err != nil {
+		return "", err
+	}
+	if dir == "" {
+		return "", fmt.Errorf("terragrunt source '%s' is not in the module cache", source)
+	}
+	return dir, nil
+}
+
+type terragruntInputs map[string]cty.Value
+
+// LoadVars returns the inputs of a terragrunt unit, which terragrunt passes to terraform as TF_VAR_ environment
+// variables
+func (inputs terragruntInputs) LoadVars() (map[string]cty.Value, error) {
+	for name := range inputs {
+		debug.Log("Setting '%s' from terragrunt inputs", name)
+	}
+	return inputs, nil
+}
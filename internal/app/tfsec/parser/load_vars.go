@@ -1,16 +1,133 @@
 package parser
 
 import (
-	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
 	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/hashicorp/hcl/v2"
 )
 
+const envVarPrefix = "TF_VAR_"
+
+// VarsSource provides values for input variables. When several sources are used, they are applied in order and values
+// from later sources take precedence - in the same way terraform applies them.
+type VarsSource interface {
+	LoadVars() (map[string]cty.Value, error)
+}
+
+type varsFile string
+
+// VarsFile is a source which reads variable values from a .tfvars or .tfvars.json file
+func VarsFile(path string) VarsSource {
+	return varsFile(path)
+}
+
+func (f varsFile) LoadVars() (map[string]cty.Value, error) {
+	return LoadTFVars(string(f))
+}
+
+type varValue struct {
+	name string
+	raw  string
+}
+
+// VarValue is a source which sets a single variable, as with terraform's -var flag
+func VarValue(name string, raw string) VarsSource {
+	return varValue{name: name, raw: raw}
+}
+
+func (v varValue) LoadVars() (map[string]cty.Value, error) {
+	debug.Log("Setting '%s' from --var", v.name)
+	return map[string]cty.Value{
+		v.name: parseRawVarValue(v.raw, "--var"),
+	}, nil
+}
+
+type envVars []string
+
+// EnvironmentVars is a source which reads variables from TF_VAR_name entries in the given environment
+func EnvironmentVars(environ []string) VarsSource {
+	return envVars(environ)
+}
+
+func (env envVars) LoadVars() (map[string]cty.Value, error) {
+	inputVars := make(map[string]cty.Value)
+	for _, entry := range env {
+		if !strings.HasPrefix(entry, envVarPrefix) {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(entry, envVarPrefix), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+		debug.Log("Setting '%s' from environment", parts[0])
+		inputVars[parts[0]] = parseRawVarValue(parts[1], entry)
+	}
+	return inputVars, nil
+}
+
+// DefaultVarsSources returns the sources terraform reads automatically for a root module, lowest precedence first:
+// TF_VAR_ environment variables, terraform.tfvars, terraform.tfvars.json and then any *.auto.tfvars(.json) files in
+// lexical order.
+func DefaultVarsSources(rootPath string, environ []string) ([]VarsSource, error) {
+
+	sources := []VarsSource{EnvironmentVars(environ)}
+
+	for _, name := range []string{"terraform.tfvars", "terraform.tfvars.json"} {
+		path := filepath.Join(rootPath, name)
+		if _, err := os.Stat(path); err == nil {
+			sources = append(sources, VarsFile(path))
+		}
+	}
+
+	fileInfos, err := ioutil.ReadDir(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var autoFiles []string
+	for _, info := range fileInfos {
+		if info.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(info.Name(), ".auto.tfvars") || strings.HasSuffix(info.Name(), ".auto.tfvars.json") {
+			autoFiles = append(autoFiles, info.Name())
+		}
+	}
+	sort.Strings(autoFiles)
+	for _, name := range autoFiles {
+		sources = append(sources, VarsFile(filepath.Join(rootPath, name)))
+	}
+
+	return sources, nil
+}
+
+// LoadVars applies each source in turn, with later sources overriding values from earlier ones
+func LoadVars(sources []VarsSource) (map[string]cty.Value, error) {
+	inputVars := make(map[string]cty.Value)
+	for _, source := range sources {
+		vars, err := source.LoadVars()
+		if err != nil {
+			return nil, err
+		}
+		for name, val := range vars {
+			inputVars[name] = val
+		}
+	}
+	return inputVars, nil
+}
+
 func LoadTFVars(filename string) (map[string]cty.Value, error) {
 
 	diskTime := metrics.Start(metrics.DiskIO)
@@ -31,13 +148,48 @@ func LoadTFVars(filename string) (map[string]cty.Value, error) {
 	hclParseTime := metrics.Start(metrics.HCLParse)
 	defer hclParseTime.Stop()
 
-	variableFile, _ := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	var variableFile *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(filename, ".json") {
+		variableFile, diags = hcljson.Parse(src, filename)
+	} else {
+		variableFile, diags = hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	attrs, _ := variableFile.Body.JustAttributes()
 
 	for _, attr := range attrs {
 		debug.Log("Setting '%s' from tfvars file at %s", attr.Name, filename)
-		inputVars[attr.Name], _ = attr.Expr.Value(&hcl.EvalContext{})
+		inputVars[attr.Name], _ = attr.Expr.Value(nil)
 	}
 
 	return inputVars, nil
 }
+
+// parseRawVarValue reads a value provided on the command line or in the environment. As with terraform, list and map
+// values are written using HCL syntax, and everything else is taken as a string.
+func parseRawVarValue(raw string, source string) cty.Value {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		expr, diags := hclsyntax.ParseExpression([]byte(trimmed), source, hcl.Pos{Line: 1, Column: 1})
+		if !diags.HasErrors() {
+			if val, diags := expr.Value(nil); !diags.HasErrors() {
+				return val
+			}
+		}
+		debug.Log("Could not parse value from %s as HCL, using it as a string", source)
+	}
+	return cty.StringVal(raw)
+}
+
+// ParseVarFlag splits a name=value pair as provided to the --var flag
+func ParseVarFlag(flag string) (VarsSource, error) {
+	parts := strings.SplitN(flag, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return nil, fmt.Errorf("invalid variable '%s', expected the format name=value", flag)
+	}
+	return VarValue(strings.TrimSpace(parts[0]), parts[1]), nil
+}
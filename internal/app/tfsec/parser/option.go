@@ -8,9 +8,11 @@ func OptionDoNotSearchTfFiles() Option {
 	}
 }
 
-func OptionWithTFVarsPath(path string) Option {
+// OptionWithVarsSources adds sources of variable values, such as tfvars files. These are applied in the order given,
+// after the sources terraform loads automatically.
+func OptionWithVarsSources(sources ...VarsSource) Option {
 	return func(p *Parser) {
-		p.tfvarsPath = path
+		p.varsSources = append(p.varsSources, sources...)
 	}
 }
 
@@ -19,3 +21,24 @@ func OptionStopOnHCLError() Option {
 		p.stopOnHCLError = true
 	}
 }
+
+// OptionWithModuleResolver sets a resolver used to find remote modules which have not been installed with terraform init
+func OptionWithModuleResolver(resolver ModuleResolver) Option {
+	return func(p *Parser) {
+		p.moduleResolver = resolver
+	}
+}
+
+// OptionWithWorkspaceName sets the value of terraform.workspace, which is "default" unless otherwise specified
+func OptionWithWorkspaceName(workspace string) Option {
+	return func(p *Parser) {
+		p.workspace = workspace
+	}
+}
+
+// OptionWithParallelism sets the number of files and modules which are read and parsed at the same time
+func OptionWithParallelism(parallelism int) Option {
+	return func(p *Parser) {
+		p.parallelism = parallelism
+	}
+}
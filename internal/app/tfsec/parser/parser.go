@@ -3,7 +3,11 @@ package parser
 import (
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+	"github.com/tfsec/tfsec/pkg/severity"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
 	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
@@ -13,12 +17,18 @@ import (
 	"path/filepath"
 )
 
+const defaultWorkspace = "default"
+
 // Parser is a tool for parsing terraform templates at a given file system location
 type Parser struct {
 	initialPath    string
-	tfvarsPath     string
+	varsSources    []VarsSource
+	moduleResolver ModuleResolver
+	workspace      string
+	parallelism    int
 	stopOnFirstTf  bool
 	stopOnHCLError bool
+	diagnostics    diagnostic.Diagnostics
 }
 
 // New creates a new Parser
@@ -26,6 +36,8 @@ func New(initialPath string, options ...Option) *Parser {
 	p := &Parser{
 		initialPath:   initialPath,
 		stopOnFirstTf: true,
+		workspace:     defaultWorkspace,
+		parallelism:   1,
 	}
 
 	for _, option := range options {
@@ -38,6 +50,12 @@ func New(initialPath string, options ...Option) *Parser {
 // ParseDirectory parses all terraform files within a given directory
 func (parser *Parser) ParseDirectory() (block.Blocks, error) {
 
+	parser.diagnostics = nil
+
+	if isTerragruntUnit(parser.initialPath) {
+		return parser.parseTerragruntUnit(parser.initialPath)
+	}
+
 	debug.Log("Finding Terraform subdirectories...")
 	t := metrics.Start(metrics.DiskIO)
 	subdirectories, err := parser.getSubdirectories(parser.initialPath)
@@ -46,30 +64,39 @@ func (parser *Parser) ParseDirectory() (block.Blocks, error) {
 	}
 	t.Stop()
 
+	// the files of all directories are parsed together, so that they can be shared between the workers
+	t = metrics.Start(metrics.DiskIO)
+	var paths []string
+	dirFileCounts := make([]int, len(subdirectories))
+	for i, dir := range subdirectories {
+		dirPaths, err := terraformFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, dirPaths...)
+		dirFileCounts[i] = len(dirPaths)
+	}
+	t.Stop()
+
+	debug.Log("Parsing %d files with parallelism of %d...", len(paths), parser.parallelism)
+	files, diagnostics, err := parseFiles(paths, parser.parallelism, parser.stopOnHCLError)
+	if err != nil {
+		return nil, err
+	}
+	parser.diagnostics = append(parser.diagnostics, diagnostics...)
+
 	var blocks block.Blocks
 
-	for _, dir := range subdirectories {
+	for i, dir := range subdirectories {
 		debug.Log("Beginning parse for directory '%s'...", dir)
-		files, err := LoadDirectory(dir, parser.stopOnHCLError)
+		dirBlocks, diagnostics, err := loadBlocksFromFiles(files[:dirFileCounts[i]], parser.stopOnHCLError)
 		if err != nil {
 			return nil, err
 		}
-
-		for _, file := range files {
-			fileBlocks, err := LoadBlocksFromFile(file)
-			if err != nil {
-				if parser.stopOnHCLError {
-					return nil, err
-				}
-				_, _ = fmt.Fprintf(os.Stderr, "WARNING: HCL error: %s\n", err)
-				continue
-			}
-			if len(fileBlocks) > 0 {
-				debug.Log("Added %d blocks from %s...", len(fileBlocks), fileBlocks[0].DefRange.Filename)
-			}
-			for _, fileBlock := range fileBlocks {
-				blocks = append(blocks, block.New(fileBlock, nil, nil))
-			}
+		parser.diagnostics = append(parser.diagnostics, diagnostics...)
+		files = files[dirFileCounts[i]:]
+		for _, dirBlock := range dirBlocks {
+			blocks = append(blocks, block.New(dirBlock, nil, nil))
 		}
 	}
 
@@ -85,9 +112,90 @@ func (parser *Parser) ParseDirectory() (block.Blocks, error) {
 		debug.Log("Project root set to '%s'...", tfPath)
 	}
 
+	varsSources, err := DefaultVarsSources(tfPath, os.Environ())
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.evaluate(tfPath, blocks, varsSources)
+}
+
+// parseTerragruntUnit parses the terraform configuration deployed by a terragrunt unit, using the inputs of the unit as
+// variable values
+func (parser *Parser) parseTerragruntUnit(unitDir string) (block.Blocks, error) {
+
+	debug.Log("Loading terragrunt configuration for '%s'...", unitDir)
+	config, diags, err := loadTerragruntConfig(unitDir)
+	if err != nil {
+		if _, ok := err.(hcl.Diagnostics); ok && !parser.stopOnHCLError {
+			parser.diagnostics = append(parser.diagnostics, diagnostic.FromError(diagnostic.Parse, err)...)
+			return nil, nil
+		}
+		return nil, err
+	}
+	parser.diagnostics = append(parser.diagnostics, diagnostic.FromHCL(diagnostic.Evaluation, diags)...)
+
+	tfPath, err := resolveTerragruntSource(unitDir, config.source, parser.moduleResolver)
+	if err != nil {
+		// as with modules, a source which is not available means the unit cannot be scanned, rather than an error
+		message := fmt.Sprintf("Failed to load terragrunt source: %s", err)
+		parser.diagnostics = append(parser.diagnostics, diagnostic.New(diagnostic.ModuleLoad, severity.Warning, message, block.Range{Filename: config.path}))
+		return nil, nil
+	}
+	debug.Log("Terragrunt unit '%s' deploys '%s'...", unitDir, tfPath)
+
+	// terragrunt copies the files of the unit over the source before running terraform, so both are read
+	dirs := []string{tfPath}
+	if tfPath != unitDir {
+		dirs = append(dirs, unitDir)
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		dirPaths, err := terraformFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, dirPaths...)
+	}
+	files, diagnostics, err := parseFiles(paths, parser.parallelism, parser.stopOnHCLError)
+	if err != nil {
+		return nil, err
+	}
+	parser.diagnostics = append(parser.diagnostics, diagnostics...)
+
+	hclBlocks, diagnostics, err := loadBlocksFromFiles(files, parser.stopOnHCLError)
+	if err != nil {
+		return nil, err
+	}
+	parser.diagnostics = append(parser.diagnostics, diagnostics...)
+
+	var blocks block.Blocks
+	for _, hclBlock := range hclBlocks {
+		blocks = append(blocks, block.New(hclBlock, nil, nil))
+	}
+	metrics.Add(metrics.BlocksLoaded, len(blocks))
+
+	// the inputs are passed as environment variables, so tfvars files in the unit take precedence over them
+	varsSources := []VarsSource{terragruntInputs(config.inputs)}
+	for _, dir := range dirs {
+		dirSources, err := DefaultVarsSources(dir, os.Environ())
+		if err != nil {
+			return nil, err
+		}
+		varsSources = append(varsSources, dirSources...)
+	}
+
+	return parser.evaluate(tfPath, blocks, varsSources)
+}
+
+// evaluate loads the modules of a root module and evaluates its blocks, using the given sources of variable values
+// followed by any given to the parser
+func (parser *Parser) evaluate(tfPath string, blocks block.Blocks, varsSources []VarsSource) (block.Blocks, error) {
+
 	debug.Log("Loading TFVars...")
-	t = metrics.Start(metrics.DiskIO)
-	inputVars, err := LoadTFVars(parser.tfvarsPath)
+	t := metrics.Start(metrics.DiskIO)
+	inputVars, err := LoadVars(append(varsSources, parser.varsSources...))
 	if err != nil {
 		return nil, err
 	}
@@ -99,20 +207,30 @@ func (parser *Parser) ParseDirectory() (block.Blocks, error) {
 	t.Stop()
 
 	debug.Log("Loading modules...")
-	modules := LoadModules(blocks, tfPath, modulesMetadata, parser.stopOnHCLError)
+	modules, moduleDiagnostics := LoadModules(blocks, tfPath, tfPath, modulesMetadata, parser.moduleResolver, parser.parallelism, parser.stopOnHCLError)
+	parser.diagnostics = append(parser.diagnostics, moduleDiagnostics...)
 	var visited []*visitedModule
 
 	debug.Log("Evaluating expressions...")
-	evaluator := NewEvaluator(tfPath, tfPath, blocks, inputVars, modulesMetadata, modules, visited, parser.stopOnHCLError)
+	evalErrors := &block.EvaluationErrors{}
+	evaluator := NewEvaluator(tfPath, tfPath, blocks, inputVars, modulesMetadata, parser.moduleResolver, modules, visited, parser.parallelism, parser.stopOnHCLError, parser.workspace, evalErrors)
 	evaluatedBlocks, err := evaluator.EvaluateAll()
 	if err != nil {
 		return nil, err
 	}
+	parser.diagnostics = append(parser.diagnostics, evaluator.Diagnostics()...)
+	parser.diagnostics = append(parser.diagnostics, diagnostic.FromEvaluationErrors(evalErrors.Take())...)
 	metrics.Add(metrics.BlocksEvaluated, len(evaluatedBlocks))
 	return evaluatedBlocks, nil
 
 }
 
+// Diagnostics returns any problems found while parsing, such as files which could not be parsed, modules which could not
+// be loaded or values which reference each other. These do not prevent a scan, but mean it may be incomplete.
+func (parser *Parser) Diagnostics() diagnostic.Diagnostics {
+	return parser.diagnostics
+}
+
 func (parser *Parser) getSubdirectories(path string) ([]string, error) {
 	entries, err := ioutil.ReadDir(path)
 	if err != nil {
@@ -121,7 +239,7 @@ func (parser *Parser) getSubdirectories(path string) ([]string, error) {
 
 	var results []string
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".tf" {
+		if !entry.IsDir() && isTerraformFile(entry.Name()) {
 			debug.Log("Found qualifying subdirectory containing .tf files: %s", path)
 			results = append(results, path)
 			if parser.stopOnFirstTf {
@@ -1,13 +1,20 @@
 package parser
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sort"
 	"testing"
 
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
 	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+	"github.com/tfsec/tfsec/pkg/severity"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -219,6 +226,1003 @@ output "result" {
 	}
 }
 
+func Test_CountExpansion(t *testing.T) {
+
+	path := createTestFile("test.tf", `
+variable "acls" {
+	default = ["private", "public-read", "private"]
+}
+
+resource "aws_s3_bucket" "counted" {
+	count = 3
+	acl = var.acls[count.index]
+}
+
+resource "aws_s3_bucket" "disabled" {
+	count = 0
+	acl = "public-read"
+}
+
+data "aws_iam_policy_document" "policy" {
+	count = length(var.acls) - 1
+	name = "policy-${count.index}"
+}
+
+output "second_acl" {
+	value = aws_s3_bucket.counted[1].acl
+}
+`)
+
+	parser := New(filepath.Dir(path), OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources := blocks.OfType("resource")
+	require.Len(t, resources, 3)
+	for i, resource := range resources {
+		assert.Equal(t, fmt.Sprintf("aws_s3_bucket.counted[%d]", i), resource.FullName())
+		assert.True(t, resource.IsExpanded())
+		acl := resource.GetAttribute("acl")
+		require.NotNil(t, acl)
+		assert.Equal(t, []string{"private", "public-read", "private"}[i], acl.Value().AsString())
+	}
+
+	datas := blocks.OfType("data")
+	require.Len(t, datas, 2)
+	assert.Equal(t, "data.aws_iam_policy_document.policy[1]", datas[1].FullName())
+	assert.Equal(t, "policy-1", datas[1].GetAttribute("name").Value().AsString())
+
+	outputs := blocks.OfType("output")
+	require.Len(t, outputs, 1)
+	assert.Equal(t, "public-read", outputs[0].GetAttribute("value").Value().AsString())
+}
+
+func Test_ForEachExpansion(t *testing.T) {
+
+	path := createTestFile("test.tf", `
+variable "groups" {
+	default = {
+		web = {
+			port = 443
+		}
+		ssh = {
+			port = 22
+		}
+	}
+}
+
+resource "aws_security_group" "sg" {
+	for_each = var.groups
+	name = each.key
+	port = each.value.port
+}
+
+resource "aws_s3_bucket" "buckets" {
+	for_each = toset(["logs", "data"])
+	bucket = "bucket-${each.value}"
+}
+
+resource "aws_s3_bucket" "none" {
+	for_each = {}
+	bucket = "none"
+}
+
+output "web_port" {
+	value = aws_security_group.sg["web"].port
+}
+`)
+
+	parser := New(filepath.Dir(path), OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources := blocks.OfType("resource")
+	require.Len(t, resources, 4)
+
+	assert.Equal(t, `aws_security_group.sg["ssh"]`, resources[0].FullName())
+	assert.Equal(t, "ssh", resources[0].InstanceKey().AsString())
+	assert.Equal(t, "ssh", resources[0].GetAttribute("name").Value().AsString())
+	assert.True(t, resources[0].GetAttribute("port").Equals(22))
+
+	assert.Equal(t, `aws_security_group.sg["web"]`, resources[1].FullName())
+	assert.True(t, resources[1].GetAttribute("port").Equals(443))
+
+	assert.Equal(t, `aws_s3_bucket.buckets["data"]`, resources[2].FullName())
+	assert.Equal(t, "bucket-data", resources[2].GetAttribute("bucket").Value().AsString())
+	assert.Equal(t, `aws_s3_bucket.buckets["logs"]`, resources[3].FullName())
+	assert.Equal(t, "bucket-logs", resources[3].GetAttribute("bucket").Value().AsString())
+
+	outputs := blocks.OfType("output")
+	require.Len(t, outputs, 1)
+	assert.True(t, outputs[0].GetAttribute("value").Equals(443))
+}
+
+func Test_VariablePrecedence(t *testing.T) {
+
+	path := createTestFile("test.tf", `
+variable "from_default" {
+	default = "default"
+}
+variable "from_env" {}
+variable "from_tfvars" {}
+variable "from_tfvars_json" {}
+variable "from_auto" {}
+variable "from_file_flag" {}
+variable "from_var_flag" {}
+variable "list_from_var_flag" {}
+
+resource "values" "all" {
+	from_default = var.from_default
+	from_env = var.from_env
+	from_tfvars = var.from_tfvars
+	from_tfvars_json = var.from_tfvars_json
+	from_auto = var.from_auto
+	from_file_flag = var.from_file_flag
+	from_var_flag = var.from_var_flag
+	list_from_var_flag = var.list_from_var_flag
+}
+`)
+	dir := filepath.Dir(path)
+	writeTestFile(t, dir, "terraform.tfvars", `
+from_env = "tfvars"
+from_tfvars = "tfvars"
+from_tfvars_json = "tfvars"
+from_auto = "tfvars"
+`)
+	writeTestFile(t, dir, "terraform.tfvars.json", `{"from_tfvars_json": "tfvars_json", "from_auto": "tfvars_json"}`)
+	writeTestFile(t, dir, "a.auto.tfvars", `from_auto = "a"`)
+	writeTestFile(t, dir, "b.auto.tfvars", `
+from_auto = "b"
+from_file_flag = "b"
+`)
+	writeTestFile(t, dir, "extra.tfvars", `
+from_file_flag = "file_flag"
+from_var_flag = "file_flag"
+`)
+
+	require.NoError(t, os.Setenv("TF_VAR_from_env", "env"))
+	require.NoError(t, os.Setenv("TF_VAR_from_tfvars", "env"))
+	defer func() {
+		_ = os.Unsetenv("TF_VAR_from_env")
+		_ = os.Unsetenv("TF_VAR_from_tfvars")
+	}()
+
+	varFlag, err := ParseVarFlag("from_var_flag=var_flag")
+	require.NoError(t, err)
+	listFlag, err := ParseVarFlag(`list_from_var_flag=["a", "b"]`)
+	require.NoError(t, err)
+
+	parser := New(dir, OptionStopOnHCLError(), OptionWithVarsSources(
+		VarsFile(filepath.Join(dir, "extra.tfvars")),
+		varFlag,
+		listFlag,
+	))
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	resources := blocks.OfType("resource")
+	require.Len(t, resources, 1)
+
+	expected := map[string]string{
+		"from_default":     "default",
+		"from_env":         "tfvars",
+		"from_tfvars":      "tfvars",
+		"from_tfvars_json": "tfvars_json",
+		"from_auto":        "b",
+		"from_file_flag":   "file_flag",
+		"from_var_flag":    "var_flag",
+	}
+	for name, value := range expected {
+		attr := resources[0].GetAttribute(name)
+		require.NotNil(t, attr, name)
+		assert.Equal(t, value, attr.Value().AsString(), name)
+	}
+
+	list := resources[0].GetAttribute("list_from_var_flag")
+	require.NotNil(t, list)
+	assert.True(t, list.Contains("b"))
+}
+
+func Test_EnvironmentVars(t *testing.T) {
+	vars, err := EnvironmentVars([]string{
+		"HOME=/root",
+		"TF_VAR_name=value",
+		"TF_VAR_map={ key = \"value\" }",
+	}).LoadVars()
+	require.NoError(t, err)
+	require.Len(t, vars, 2)
+	assert.Equal(t, "value", vars["name"].AsString())
+	assert.Equal(t, "value", vars["map"].GetAttr("key").AsString())
+}
+
+func writeTestFile(t *testing.T, dir string, filename string, contents string) {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, filename), []byte(contents), 0600))
+}
+
+func Test_JSONParsing(t *testing.T) {
+
+	path := createTestFile("test.tf.json", `{
+	"variable": {
+		"acl": {
+			"default": "public-read"
+		}
+	},
+	"locals": {
+		"prefix": "tfsec"
+	},
+	"resource": {
+		"aws_s3_bucket": {
+			"bucket": {
+				"bucket": "${local.prefix}-bucket",
+				"acl": "${var.acl}",
+				"tags": {
+					"Name": "bucket"
+				},
+				"logging": {
+					"target_bucket": "logs"
+				}
+			}
+		}
+	}
+}`)
+
+	parser := New(filepath.Dir(path), OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	resources := blocks.OfType("resource")
+	require.Len(t, resources, 1)
+	bucket := resources[0]
+	assert.Equal(t, "aws_s3_bucket.bucket", bucket.FullName())
+	assert.Equal(t, 12, bucket.Range().StartLine)
+	assert.Equal(t, 21, bucket.Range().EndLine)
+
+	assert.Equal(t, "tfsec-bucket", bucket.GetAttribute("bucket").Value().AsString())
+	assert.Equal(t, "public-read", bucket.GetAttribute("acl").Value().AsString())
+	assert.True(t, bucket.GetAttribute("tags").Contains("Name"))
+	assert.Equal(t, 14, bucket.GetAttribute("acl").Range().StartLine)
+
+	logging := bucket.GetBlock("logging")
+	require.NotNil(t, logging)
+	assert.Equal(t, "logs", logging.GetAttribute("target_bucket").Value().AsString())
+	assert.Nil(t, bucket.GetBlock("versioning"))
+}
+
+func Test_OverrideFiles(t *testing.T) {
+
+	path := createTestFile("main.tf", `
+variable "acl" {
+	default = "private"
+}
+
+locals {
+	name = "original"
+	region = "eu-west-1"
+}
+
+resource "aws_s3_bucket" "bucket" {
+	bucket = local.name
+	acl = var.acl
+	versioning {
+		enabled = false
+	}
+	logging {
+		target_bucket = "first"
+	}
+	logging {
+		target_bucket = "second"
+	}
+}
+`)
+	dir := filepath.Dir(path)
+	writeTestFile(t, dir, "override.tf", `
+variable "acl" {
+	default = "public-read"
+}
+
+locals {
+	name = "overridden"
+}
+`)
+	writeTestFile(t, dir, "bucket_override.tf", `
+resource "aws_s3_bucket" "bucket" {
+	logging {
+		target_bucket = "override"
+	}
+}
+`)
+
+	parser := New(dir, OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	require.Len(t, blocks.OfType("variable"), 1)
+	require.Len(t, blocks.OfType("locals"), 1)
+
+	resources := blocks.OfType("resource")
+	require.Len(t, resources, 1)
+	bucket := resources[0]
+	assert.Equal(t, path, bucket.Range().Filename)
+
+	assert.Equal(t, "overridden", bucket.GetAttribute("bucket").Value().AsString())
+
+	acl := bucket.GetAttribute("acl")
+	require.NotNil(t, acl)
+	assert.Equal(t, "public-read", acl.Value().AsString())
+	assert.Equal(t, path, acl.Range().Filename)
+
+	versioning := bucket.GetBlock("versioning")
+	require.NotNil(t, versioning)
+	assert.True(t, versioning.GetAttribute("enabled").IsFalse())
+
+	logging := bucket.GetBlocks("logging")
+	require.Len(t, logging, 1)
+	assert.Equal(t, "override", logging[0].GetAttribute("target_bucket").Value().AsString())
+	assert.Equal(t, filepath.Join(dir, "bucket_override.tf"), logging[0].GetAttribute("target_bucket").Range().Filename)
+}
+
+func Test_UnmatchedOverridesAreReportedAsDiagnostics(t *testing.T) {
+
+	path := createTestFile("main.tf", `
+locals {
+	name = "original"
+}
+`)
+	dir := filepath.Dir(path)
+	writeTestFile(t, dir, "override.tf", `
+locals {
+	missing = "value"
+}
+
+resource "aws_s3_bucket" "missing" {
+}
+`)
+
+	parser := New(dir, OptionStopOnHCLError())
+	_, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	var messages []string
+	for _, diag := range parser.Diagnostics() {
+		assert.Equal(t, diagnostic.Parse, diag.Phase)
+		assert.Equal(t, severity.Warning, diag.Severity)
+		assert.Equal(t, filepath.Join(dir, "override.tf"), diag.Range.Filename)
+		messages = append(messages, diag.Message)
+	}
+	assert.ElementsMatch(t, []string{
+		"Override for local 'missing' has no matching local to override",
+		"Override has no matching block to override",
+	}, messages)
+}
+
+func Test_ModuleExpansion(t *testing.T) {
+
+	path := createTestFileWithModule(`
+variable "environments" {
+	default = {
+		dev  = "private"
+		prod = "public-read"
+	}
+}
+
+module "env" {
+	for_each = var.environments
+	source = "../module"
+	name = each.key
+	acl = each.value
+}
+
+module "counted" {
+	count = 2
+	source = "../module"
+	name = "counted-${count.index}"
+	acl = "private"
+}
+
+module "disabled" {
+	count = 0
+	source = "../module"
+	name = "disabled"
+	acl = "private"
+}
+
+output "prod_bucket" {
+	value = module.env["prod"].bucket
+}
+
+output "second_bucket" {
+	value = module.counted[1].bucket
+}
+`,
+		`
+variable "name" {}
+variable "acl" {}
+
+resource "aws_s3_bucket" "logs" {
+	bucket = "${var.name}-logs"
+	acl = var.acl
+}
+
+output "bucket" {
+	value = aws_s3_bucket.logs.bucket
+}
+`,
+		"module",
+	)
+
+	parser := New(path, OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	buckets := make(map[string]*block.Block)
+	for _, resource := range blocks.OfType("resource") {
+		buckets[resource.FullName()] = resource
+	}
+	require.Len(t, buckets, 4)
+
+	dev := buckets[`module.env["dev"]:aws_s3_bucket.logs`]
+	require.NotNil(t, dev)
+	assert.Equal(t, "dev-logs", dev.GetAttribute("bucket").Value().AsString())
+	assert.Equal(t, "private", dev.GetAttribute("acl").Value().AsString())
+
+	prod := buckets[`module.env["prod"]:aws_s3_bucket.logs`]
+	require.NotNil(t, prod)
+	assert.Equal(t, "prod-logs", prod.GetAttribute("bucket").Value().AsString())
+	assert.Equal(t, "public-read", prod.GetAttribute("acl").Value().AsString())
+
+	counted := buckets["module.counted[1]:aws_s3_bucket.logs"]
+	require.NotNil(t, counted)
+	assert.Equal(t, "counted-1-logs", counted.GetAttribute("bucket").Value().AsString())
+	assert.NotNil(t, buckets["module.counted[0]:aws_s3_bucket.logs"])
+
+	outputs := make(map[string]*block.Block)
+	for _, output := range blocks.OfType("output") {
+		outputs[output.FullName()] = output
+	}
+	require.NotNil(t, outputs["output.prod_bucket"])
+	assert.Equal(t, "prod-logs", outputs["output.prod_bucket"].GetAttribute("value").Value().AsString())
+	require.NotNil(t, outputs["output.second_bucket"])
+	assert.Equal(t, "counted-1-logs", outputs["output.second_bucket"].GetAttribute("value").Value().AsString())
+}
+
+func Test_ModuleCache(t *testing.T) {
+
+	cacheDir, err := ioutil.TempDir(os.TempDir(), "tfsec-cache")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(cacheDir) }()
+
+	bucket := func(acl string) string {
+		return fmt.Sprintf(`
+resource "aws_s3_bucket" "bucket" {
+	acl = "%s"
+}
+`, acl)
+	}
+
+	// registry mirror, with a nested module referenced relative to the registry module
+	for _, v := range []string{"1.0.0", "1.2.0", "2.0.0"} {
+		versionDir := filepath.Join(cacheDir, "registry", "example", "buckets", "aws", v)
+		require.NoError(t, os.MkdirAll(filepath.Join(versionDir, "modules", "inner"), 0700))
+		writeTestFile(t, versionDir, "main.tf", `
+module "inner" {
+	source = "./modules/inner"
+}
+`)
+		writeTestFile(t, filepath.Join(versionDir, "modules", "inner"), "main.tf", bucket("registry-"+v))
+	}
+
+	// bare git repository, with the module at a tagged commit
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	workDir := filepath.Join(cacheDir, "work")
+	require.NoError(t, os.MkdirAll(workDir, 0700))
+	runGitCommand := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=tfsec", "-c", "user.email=tfsec@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	runGitCommand(workDir, "init", "-q")
+	writeTestFile(t, workDir, "main.tf", bucket("git-tagged"))
+	runGitCommand(workDir, "add", "main.tf")
+	runGitCommand(workDir, "commit", "-q", "-m", "tagged")
+	runGitCommand(workDir, "tag", "v1.0.0")
+	writeTestFile(t, workDir, "main.tf", bucket("git-latest"))
+	runGitCommand(workDir, "commit", "-q", "-a", "-m", "latest")
+	runGitCommand(cacheDir, "clone", "-q", "--bare", workDir, filepath.Join(cacheDir, "git", "example.com", "org", "repo.git"))
+
+	// archive
+	archiveDir := filepath.Join(cacheDir, "archives", "example.com", "modules")
+	require.NoError(t, os.MkdirAll(archiveDir, 0700))
+	archiveFile, err := os.Create(filepath.Join(archiveDir, "buckets.tar.gz"))
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(archiveFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+	contents := []byte(bucket("archive"))
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "sub/main.tf", Mode: 0600, Size: int64(len(contents))}))
+	_, err = tarWriter.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, archiveFile.Close())
+
+	path := createTestFile("test.tf", `
+module "registry" {
+	source = "example/buckets/aws"
+	version = "~> 1.0"
+}
+
+module "git_tagged" {
+	source = "git::https://example.com/org/repo.git?ref=v1.0.0"
+}
+
+module "git_head" {
+	source = "git::ssh://git@example.com/org/repo.git"
+}
+
+module "archive" {
+	source = "https://example.com/modules/buckets.tar.gz//sub"
+}
+`)
+
+	parser := New(filepath.Dir(path), OptionStopOnHCLError(), OptionWithModuleResolver(ModuleCache(cacheDir)))
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	acls := make(map[string]string)
+	for _, resource := range blocks.OfType("resource") {
+		acls[resource.FullName()] = resource.GetAttribute("acl").Value().AsString()
+	}
+
+	assert.Equal(t, map[string]string{
+		"module.registry:module.inner:aws_s3_bucket.bucket": "registry-1.2.0",
+		"module.git_tagged:aws_s3_bucket.bucket":            "git-tagged",
+		"module.git_head:aws_s3_bucket.bucket":              "git-latest",
+		"module.archive:aws_s3_bucket.bucket":               "archive",
+	}, acls)
+}
+
+func Test_PathAndWorkspaceValues(t *testing.T) {
+
+	path := createTestFileWithModule(`
+module "policy" {
+	source = "../module"
+}
+
+resource "aws_s3_bucket" "bucket" {
+	acl = terraform.workspace == "prod" ? "private" : "public-read"
+	bucket = basename(path.root)
+}
+`,
+		`
+resource "aws_iam_policy" "policy" {
+	policy = file("${path.module}/policy.json")
+	description = path.cwd
+}
+`,
+		"module",
+	)
+	writeTestFile(t, filepath.Join(filepath.Dir(path), "module"), "policy.json", `{"Version": "2012-10-17"}`)
+
+	for workspace, expectedACL := range map[string]string{
+		"":     "public-read",
+		"prod": "private",
+	} {
+		t.Run(workspace, func(t *testing.T) {
+			var options []Option
+			if workspace != "" {
+				options = append(options, OptionWithWorkspaceName(workspace))
+			}
+			blocks, err := New(path, options...).ParseDirectory()
+			require.NoError(t, err)
+
+			buckets := blocks.OfType("resource")
+			require.Len(t, buckets, 2)
+			for _, resource := range buckets {
+				switch resource.FullName() {
+				case "aws_s3_bucket.bucket":
+					assert.Equal(t, expectedACL, resource.GetAttribute("acl").Value().AsString())
+					assert.Equal(t, "main", resource.GetAttribute("bucket").Value().AsString())
+				case "module.policy:aws_iam_policy.policy":
+					assert.Equal(t, `{"Version": "2012-10-17"}`, resource.GetAttribute("policy").Value().AsString())
+					assert.Equal(t, path, resource.GetAttribute("description").Value().AsString())
+				default:
+					t.Errorf("unexpected resource %s", resource.FullName())
+				}
+			}
+		})
+	}
+}
+
+func Test_ParsePlan(t *testing.T) {
+
+	path := createTestFileWithModule(`
+variable "acl" {}
+
+resource "aws_s3_bucket" "logs" {
+	count = 2
+	bucket = "logs-${count.index}"
+	acl = var.acl
+	versioning {
+		enabled = true
+	}
+}
+
+module "web" {
+	for_each = toset(["public"])
+	source = "../module"
+}
+`,
+		`
+resource "aws_s3_bucket" "web" {
+	acl = "public-read"
+}
+`,
+		"module",
+	)
+
+	planPath := filepath.Join(filepath.Dir(path), "plan.json")
+	writeTestFile(t, filepath.Dir(path), "plan.json", `{
+	"format_version": "0.2",
+	"planned_values": {
+		"root_module": {
+			"resources": [
+				{
+					"address": "aws_s3_bucket.logs[0]",
+					"mode": "managed",
+					"type": "aws_s3_bucket",
+					"name": "logs",
+					"index": 0,
+					"values": {"bucket": "logs-0", "acl": "private", "tags": null, "versioning": [{"enabled": true}], "grant": []}
+				},
+				{
+					"address": "aws_s3_bucket.logs[1]",
+					"mode": "managed",
+					"type": "aws_s3_bucket",
+					"name": "logs",
+					"index": 1,
+					"values": {"bucket": "logs-1", "acl": "private", "tags": null, "versioning": [{"enabled": true}], "grant": []}
+				}
+			],
+			"child_modules": [
+				{
+					"address": "module.web[\"public\"]",
+					"resources": [
+						{
+							"address": "module.web[\"public\"].aws_s3_bucket.web",
+							"mode": "managed",
+							"type": "aws_s3_bucket",
+							"name": "web",
+							"values": {"acl": "public-read"}
+						}
+					]
+				}
+			]
+		}
+	},
+	"resource_changes": [
+		{
+			"address": "aws_s3_bucket.logs[0]",
+			"change": {"actions": ["create"], "after_unknown": {"arn": true, "versioning": [{}]}}
+		}
+	],
+	"configuration": {
+		"root_module": {
+			"module_calls": {
+				"web": {
+					"source": "../module",
+					"module": {}
+				}
+			}
+		}
+	}
+}`)
+
+	blocks, err := New(path).ParsePlan(planPath)
+	require.NoError(t, err)
+
+	resources := make(map[string]*block.Block)
+	for _, resource := range blocks.OfType("resource") {
+		resources[resource.FullName()] = resource
+	}
+	require.Len(t, resources, 3)
+
+	first := resources["aws_s3_bucket.logs[0]"]
+	require.NotNil(t, first)
+	assert.Equal(t, "private", first.GetAttribute("acl").Value().AsString())
+	assert.Equal(t, 7, first.GetAttribute("acl").Range().StartLine)
+	assert.Equal(t, filepath.Join(path, "main.tf"), first.Range().Filename)
+	assert.Equal(t, 4, first.Range().StartLine)
+	assert.Equal(t, cty.NilVal, first.GetAttribute("arn").Value())
+	assert.Nil(t, first.GetAttribute("tags"))
+	assert.Nil(t, first.GetAttribute("grant"))
+	versioning := first.GetBlock("versioning")
+	require.NotNil(t, versioning)
+	assert.True(t, versioning.GetAttribute("enabled").IsTrue())
+	assert.Equal(t, 8, versioning.Range().StartLine)
+
+	assert.Equal(t, "logs-1", resources["aws_s3_bucket.logs[1]"].GetAttribute("bucket").Value().AsString())
+
+	web := resources[`module.web["public"]:aws_s3_bucket.web`]
+	require.NotNil(t, web)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "module", "main.tf"), web.Range().Filename)
+	assert.Equal(t, "public-read", web.GetAttribute("acl").Value().AsString())
+}
+
+func Test_LoadState(t *testing.T) {
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+	require.NoError(t, err)
+	writeTestFile(t, dir, "terraform.tfstate", `{
+	"version": 4,
+	"terraform_version": "0.15.3",
+	"resources": [
+		{
+			"mode": "managed",
+			"type": "aws_s3_bucket",
+			"name": "imported",
+			"provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+			"instances": [
+				{
+					"schema_version": 0,
+					"attributes": {"bucket": "imported", "acl": "public-read", "versioning": [{"enabled": false, "mfa_delete": false}]}
+				}
+			]
+		},
+		{
+			"module": "module.logs[\"eu\"]",
+			"mode": "managed",
+			"type": "aws_s3_bucket",
+			"name": "logs",
+			"each": "list",
+			"instances": [
+				{"index_key": 0, "attributes": {"bucket": "logs-0"}},
+				{"index_key": 1, "attributes": {"bucket": "logs-1"}},
+				{"index_key": 1, "deposed": "00000001", "attributes": {"bucket": "logs-1-old"}}
+			]
+		}
+	]
+}`)
+
+	blocks, err := LoadState(filepath.Join(dir, "terraform.tfstate"))
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+
+	imported := blocks[0]
+	assert.Equal(t, "aws_s3_bucket.imported", imported.Address())
+	assert.Equal(t, "public-read", imported.GetAttribute("acl").Value().AsString())
+	assert.True(t, imported.GetBlock("versioning").GetAttribute("enabled").IsFalse())
+	assert.Equal(t, "", imported.Range().Filename)
+
+	assert.Equal(t, `module.logs["eu"].aws_s3_bucket.logs[0]`, blocks[1].Address())
+	assert.Equal(t, `module.logs["eu"].aws_s3_bucket.logs[1]`, blocks[2].Address())
+	assert.Equal(t, "logs-1", blocks[2].GetAttribute("bucket").Value().AsString())
+
+	writeTestFile(t, dir, "old.tfstate", `{"version": 3}`)
+	_, err = LoadState(filepath.Join(dir, "old.tfstate"))
+	assert.Error(t, err)
+}
+
+func Test_DependencyOrderedEvaluation(t *testing.T) {
+
+	// each local references the one declared after it, so the chain is resolved from the end of the file backwards
+	var contents string
+	for i := 0; i < 64; i++ {
+		contents += fmt.Sprintf("locals {\n\tlink_%d = local.link_%d\n}\n", i, i+1)
+	}
+	contents += `
+locals {
+	link_64 = var.name
+}
+
+variable "name" {
+	default = "chained"
+}
+
+module "first" {
+	source = "../module"
+	name = local.link_0
+	acl = "private"
+}
+
+module "second" {
+	source = "../module"
+	name = module.first.bucket
+	acl = "private"
+}
+
+resource "aws_s3_bucket" "root" {
+	bucket = module.second.bucket
+}
+`
+
+	path := createTestFileWithModule(contents, `
+variable "name" {}
+variable "acl" {}
+
+resource "aws_s3_bucket" "logs" {
+	bucket = "${var.name}-logs"
+	acl = var.acl
+}
+
+output "bucket" {
+	value = aws_s3_bucket.logs.bucket
+}
+`, "module")
+
+	parser := New(path, OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+	assert.Empty(t, parser.Diagnostics())
+
+	buckets := make(map[string]string)
+	for _, resource := range blocks.OfType("resource") {
+		buckets[resource.FullName()] = resource.GetAttribute("bucket").Value().AsString()
+	}
+	assert.Equal(t, map[string]string{
+		"module.first:aws_s3_bucket.logs":  "chained-logs",
+		"module.second:aws_s3_bucket.logs": "chained-logs-logs",
+		"aws_s3_bucket.root":               "chained-logs-logs",
+	}, buckets)
+}
+
+func Test_ReferenceCycles(t *testing.T) {
+
+	path := createTestFile("test.tf", `
+locals {
+	a = local.b
+	b = local.a
+	name = "independent"
+}
+
+resource "aws_s3_bucket" "self" {
+	bucket = aws_s3_bucket.self.id
+}
+
+resource "aws_s3_bucket" "other" {
+	bucket = local.name
+	tags = { cycle = local.a }
+}
+`)
+
+	parser := New(filepath.Dir(path), OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	diagnostics := parser.Diagnostics()
+	require.Len(t, diagnostics, 2)
+	var messages []string
+	for _, diag := range diagnostics {
+		assert.Equal(t, diagnostic.Evaluation, diag.Phase)
+		assert.Equal(t, severity.Warning, diag.Severity)
+		assert.Equal(t, path, diag.Range.Filename)
+		messages = append(messages, diag.Message)
+	}
+	assert.Contains(t, messages, "Cycle in references; The following values reference each other, so they may not be fully evaluated: local.a, local.b.")
+	assert.Contains(t, messages, "Cycle in references; The following values reference each other, so they may not be fully evaluated: aws_s3_bucket.self.")
+
+	// values outside of the cycles are still evaluated
+	for _, resource := range blocks.OfType("resource") {
+		if resource.NameLabel() == "other" {
+			assert.Equal(t, "independent", resource.GetAttribute("bucket").Value().AsString())
+		}
+	}
+}
+
+func Test_VariableTypeConstraints(t *testing.T) {
+
+	path := createTestFileWithModule(`
+module "bucket" {
+	source = "../module"
+	config = {
+		name = "logs"
+		versioned = "true"
+	}
+	environment = "staging"
+}
+`, `
+variable "config" {
+	type = object({
+		name = string
+		versioned = bool
+		encryption = optional(object({
+			enabled = optional(bool, true)
+			algorithm = optional(string, "aws:kms")
+		}), {})
+		tags = optional(map(string))
+	})
+}
+
+variable "environment" {
+	type = string
+	validation {
+		condition = contains(["dev", "prod"], var.environment)
+		error_message = "The environment must be dev or prod, not ${var.environment}."
+	}
+}
+
+resource "aws_s3_bucket" "bucket" {
+	bucket = var.config.name
+	versioning = var.config.versioned
+	sse_algorithm = var.config.encryption.enabled ? var.config.encryption.algorithm : null
+	tags = var.config.tags
+}
+`, "module")
+
+	parser := New(path, OptionStopOnHCLError())
+	blocks, err := parser.ParseDirectory()
+	require.NoError(t, err)
+
+	buckets := blocks.OfType("resource")
+	require.Len(t, buckets, 1)
+	bucket := buckets[0]
+	assert.Equal(t, "logs", bucket.GetAttribute("bucket").Value().AsString())
+	assert.Equal(t, cty.True, bucket.GetAttribute("versioning").Value())
+	assert.Equal(t, "aws:kms", bucket.GetAttribute("sse_algorithm").Value().AsString())
+	assert.True(t, bucket.GetAttribute("tags").Value().IsNull())
+
+	diagnostics := parser.Diagnostics()
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, diagnostic.Evaluation, diagnostics[0].Phase)
+	assert.Equal(t, severity.Error, diagnostics[0].Severity)
+	assert.Equal(t, `Invalid value for variable "environment": The environment must be dev or prod, not staging.`, diagnostics[0].Message)
+	assert.Equal(t, 17, diagnostics[0].Range.StartLine)
+}
+
+func Test_ParallelParsingIsDeterministic(t *testing.T) {
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for d := 0; d < 4; d++ {
+		subdir := filepath.Join(dir, fmt.Sprintf("project-%d", d))
+		require.NoError(t, os.MkdirAll(subdir, 0700))
+		for f := 0; f < 8; f++ {
+			writeTestFile(t, subdir, fmt.Sprintf("file-%d.tf", f), fmt.Sprintf(`
+module "shared_%d_%d" {
+	source = "./module"
+	name = "bucket-%d-%d"
+}
+`, d, f, d, f))
+		}
+	}
+	moduleDir := filepath.Join(dir, "module")
+	require.NoError(t, os.MkdirAll(moduleDir, 0700))
+	writeTestFile(t, moduleDir, "main.tf", `
+variable "name" {}
+
+resource "aws_s3_bucket" "bucket" {
+	bucket = var.name
+}
+`)
+
+	parse := func(parallelism int) []string {
+		blocks, err := New(dir, OptionDoNotSearchTfFiles(), OptionStopOnHCLError(), OptionWithParallelism(parallelism)).ParseDirectory()
+		require.NoError(t, err)
+		var names []string
+		for _, b := range blocks {
+			name := b.FullName() + "@" + b.Range().String()
+			if bucket := b.GetAttribute("bucket"); bucket != nil {
+				name += "=" + bucket.Value().GoString()
+			}
+			names = append(names, name)
+		}
+		return names
+	}
+
+	serial := parse(1)
+	require.NotEmpty(t, serial)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, serial, parse(8))
+	}
+}
+
 func createTestFile(filename, contents string) string {
 	dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
 	if err != nil {
@@ -263,3 +1267,240 @@ func createTestFileWithModule(contents string, moduleContents string, moduleName
 
 	return rootPath
 }
+
+func Test_FindRootModules(t *testing.T) {
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, subdir := range []string{"network", "storage", "modules/bucket", "legacy", ".terraform/modules/x"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, subdir), 0700))
+	}
+
+	// both stacks declare the same variable, which must be evaluated separately for each of them
+	writeTestFile(t, filepath.Join(dir, "network"), "main.tf", `
+terraform {
+	backend "s3" {}
+}
+
+variable "name" {}
+
+module "bucket" {
+	source = "../modules/bucket"
+	name = var.name
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "network"), "terraform.tfvars", `name = "network"`)
+	writeTestFile(t, filepath.Join(dir, "storage"), "main.tf", `
+variable "name" {}
+
+module "bucket" {
+	source = "../modules/bucket"
+	name = var.name
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "storage"), "terraform.tfvars", `name = "storage"`)
+	writeTestFile(t, filepath.Join(dir, "modules/bucket"), "main.tf", `
+variable "name" {}
+
+resource "aws_s3_bucket" "bucket" {
+	bucket = var.name
+}
+`)
+	// a called module which configures its own provider is still scanned as a root
+	writeTestFile(t, filepath.Join(dir, "legacy"), "main.tf", `
+provider "aws" {}
+`)
+	writeTestFile(t, filepath.Join(dir, "storage"), "legacy.tf", `
+module "legacy" {
+	source = "../legacy"
+}
+`)
+	writeTestFile(t, filepath.Join(dir, ".terraform/modules/x"), "main.tf", `
+resource "aws_s3_bucket" "downloaded" {}
+`)
+
+	roots, err := New(dir).FindRootModules()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "legacy"),
+		filepath.Join(dir, "network"),
+		filepath.Join(dir, "storage"),
+	}, roots)
+
+	for _, root := range roots[1:] {
+		blocks, err := New(root, OptionStopOnHCLError()).ParseDirectory()
+		require.NoError(t, err)
+		var buckets []string
+		for _, b := range blocks.OfType("resource") {
+			buckets = append(buckets, b.GetAttribute("bucket").Value().AsString())
+		}
+		assert.Equal(t, []string{filepath.Base(root)}, buckets)
+	}
+}
+
+func Test_TerragruntUnits(t *testing.T) {
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, subdir := range []string{"prod/bucket", "prod/kms", "modules/bucket"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, subdir), 0700))
+	}
+
+	writeTestFile(t, dir, "terragrunt.hcl", `
+inputs = {
+	region = "eu-west-1"
+	acl = "private"
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "prod/bucket"), "terragrunt.hcl", `
+include "root" {
+	path = find_in_parent_folders()
+}
+
+locals {
+	name = "${local.prefix}-bucket"
+	prefix = "prod"
+}
+
+terraform {
+	source = "../../modules//bucket"
+}
+
+dependency "kms" {
+	config_path = "../kms"
+	mock_outputs = {
+		key_arn = "arn:mock"
+	}
+}
+
+inputs = {
+	name = local.name
+	acl = "public-read"
+	kms_key = dependency.kms.outputs.key_arn
+	path = path_relative_to_include()
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "prod/kms"), "terragrunt.hcl", `
+terraform {
+	source = "git::https://example.com/modules.git//kms?ref=v1"
+}
+`)
+	writeTestFile(t, filepath.Join(dir, "modules/bucket"), "main.tf", `
+variable "name" {}
+variable "acl" {}
+variable "kms_key" {}
+variable "region" {}
+variable "path" {}
+
+resource "aws_s3_bucket" "bucket" {
+	bucket = var.name
+	acl = var.acl
+	kms_key = var.kms_key
+	region = var.region
+	path = var.path
+}
+`)
+
+	assert.True(t, IsTerragruntRepository(dir))
+	assert.False(t, IsTerragruntRepository(filepath.Join(dir, "prod/bucket")))
+
+	roots, err := New(dir).FindRootModules()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "prod/bucket"),
+		filepath.Join(dir, "prod/kms"),
+	}, roots)
+
+	blocks, err := New(filepath.Join(dir, "prod/bucket"), OptionStopOnHCLError()).ParseDirectory()
+	require.NoError(t, err)
+	buckets := blocks.OfType("resource")
+	require.Len(t, buckets, 1)
+	assert.Equal(t, filepath.Join(dir, "modules/bucket/main.tf"), buckets[0].Range().Filename)
+	for attribute, expected := range map[string]string{
+		"bucket":  "prod-bucket",
+		"acl":     "public-read",
+		"kms_key": "arn:mock",
+		"region":  "eu-west-1",
+		"path":    "prod/bucket",
+	} {
+		assert.Equal(t, expected, buckets[0].GetAttribute(attribute).Value().AsString(), attribute)
+	}
+
+	// the parent configuration at the root has no source, so the directories below it are parsed as usual
+	blocks, err = New(dir, OptionStopOnHCLError()).ParseDirectory()
+	require.NoError(t, err)
+	buckets = blocks.OfType("resource")
+	require.Len(t, buckets, 1)
+	assert.Equal(t, filepath.Join(dir, "modules/bucket/main.tf"), buckets[0].Range().Filename)
+
+	// remote sources can only be scanned if they can be found in a module cache
+	kmsParser := New(filepath.Join(dir, "prod/kms"))
+	blocks, err = kmsParser.ParseDirectory()
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+	require.Len(t, kmsParser.Diagnostics(), 1)
+	assert.Equal(t, diagnostic.ModuleLoad, kmsParser.Diagnostics()[0].Phase)
+	assert.Equal(t, filepath.Join(dir, "prod/kms", "terragrunt.hcl"), kmsParser.Diagnostics()[0].Range.Filename)
+}
+
+func Test_IsTerragruntRepository(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		files    map[string]string
+		expected bool
+	}{
+		{
+			name: "units below a parent configuration",
+			files: map[string]string{
+				"terragrunt.hcl":      `inputs = {}`,
+				"prod/terragrunt.hcl": `terraform { source = "../modules//bucket" }`,
+			},
+			expected: true,
+		},
+		{
+			name: "units without a parent configuration",
+			files: map[string]string{
+				"prod/terragrunt.hcl": `terraform { source = "../modules//bucket" }`,
+			},
+			expected: true,
+		},
+		{
+			name: "terraform files in the directory",
+			files: map[string]string{
+				"main.tf":             `resource "aws_s3_bucket" "bucket" {}`,
+				"prod/terragrunt.hcl": `terraform { source = "../modules//bucket" }`,
+			},
+		},
+		{
+			name: "the directory is a unit",
+			files: map[string]string{
+				"terragrunt.hcl":      `terraform { source = "./modules//bucket" }`,
+				"prod/terragrunt.hcl": `terraform { source = "../modules//bucket" }`,
+			},
+		},
+		{
+			name: "no terragrunt units",
+			files: map[string]string{
+				"modules/bucket/main.tf": `resource "aws_s3_bucket" "bucket" {}`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+			require.NoError(t, err)
+			defer func() { _ = os.RemoveAll(dir) }()
+			for path, contents := range test.files {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0700))
+				writeTestFile(t, dir, path, contents)
+			}
+			assert.Equal(t, test.expected, IsTerragruntRepository(dir))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+)
+
+var archiveExtensions = []string{"tar.gz", "tgz", "zip", "tar"}
+
+type archives string
+
+// Archives resolves module sources which refer to an archive (e.g. "https://example.com/modules/vpc.tar.gz") from
+// copies on disk, laid out as <host>/<path>. Sources which are absolute paths to an archive are read directly.
+func Archives(dir string) ModuleResolver {
+	return archives(dir)
+}
+
+func (a archives) ResolveModule(source string, _ string) (string, error) {
+
+	source, subdir := splitSubdir(source)
+
+	// a forced getter such as s3:: or gcs:: does not change where we look for the archive
+	if idx := strings.Index(source, "::"); idx > -1 {
+		if strings.HasPrefix(source, "git::") {
+			return "", nil
+		}
+		source = source[idx+2:]
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", nil
+	}
+
+	archiveType := u.Query().Get("archive")
+	if archiveType == "" {
+		archiveType = archiveTypeOf(u.Path)
+	}
+	if archiveType == "" {
+		return "", nil
+	}
+
+	var archivePath string
+	switch {
+	case u.Scheme == "file" || (u.Scheme == "" && filepath.IsAbs(u.Path)):
+		archivePath = filepath.FromSlash(u.Path)
+	case u.Host != "":
+		archivePath = filepath.Join(string(a), u.Hostname(), filepath.FromSlash(u.Path))
+	default:
+		return "", nil
+	}
+
+	hash, err := hashFile(archivePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("archive for module '%s' is not in the module cache at %s", source, archivePath)
+		}
+		return "", err
+	}
+
+	dir, err := extractedModuleDir("archive-"+hash, func(dir string) error {
+		return extractArchive(archivePath, archiveType, dir)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	debug.Log("Resolved module '%s' from archive %s", source, archivePath)
+	return filepath.Join(dir, subdir), nil
+}
+
+func archiveTypeOf(path string) string {
+	for _, extension := range archiveExtensions {
+		if strings.HasSuffix(path, "."+extension) {
+			return extension
+		}
+	}
+	return ""
+}
+
+func extractArchive(filename string, archiveType string, dir string) error {
+
+	if archiveType == "zip" {
+		return extractZip(filename, dir)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	switch archiveType {
+	case "tar":
+		return extractTar(f, dir)
+	case "tar.gz", "tgz":
+		reader, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = reader.Close() }()
+		return extractTar(reader, dir)
+	}
+
+	return fmt.Errorf("unsupported archive type '%s'", archiveType)
+}
+
+func hashFile(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
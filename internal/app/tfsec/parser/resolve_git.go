@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+)
+
+var scpLikeGitPattern = regexp.MustCompile(`^(?:[A-Za-z0-9_.-]+@)?([A-Za-z0-9.-]+):([^/][^:]*)$`)
+
+type gitRepositories string
+
+// GitRepositories resolves git module sources (e.g. "git::https://example.com/org/repo.git?ref=v1.2.0") from bare
+// repositories on disk, laid out as <host>/<path>.git. The module is read from the commit given by the ?ref= argument,
+// or HEAD if there is none. No network access is made.
+func GitRepositories(dir string) ModuleResolver {
+	return gitRepositories(dir)
+}
+
+func (g gitRepositories) ResolveModule(source string, _ string) (string, error) {
+
+	source, subdir := splitSubdir(source)
+
+	host, repoPath, ref, ok := parseGitSource(source)
+	if !ok {
+		return "", nil
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	repo := filepath.Join(string(g), host, filepath.FromSlash(repoPath)+".git")
+	if _, err := os.Stat(repo); err != nil {
+		return "", fmt.Errorf("git repository for module '%s' is not in the module cache at %s", source, repo)
+	}
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+	commit, err := runGit(repo, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("could not find ref '%s' for module '%s': %w", ref, source, err)
+	}
+	commit = strings.TrimSpace(commit)
+
+	dir, err := extractedModuleDir("git-"+commit, func(dir string) error {
+		cmd := exec.Command("git", "--git-dir", repo, "archive", "--format=tar", commit)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		if err := extractTar(stdout, dir); err != nil {
+			_ = cmd.Wait()
+			return err
+		}
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	debug.Log("Resolved git module '%s' to commit %s", source, commit)
+	return filepath.Join(dir, subdir), nil
+}
+
+// parseGitSource reads the host, repository path and ref from a git module source. Sources are recognised in the same
+// way as terraform: with a git:: prefix, as a github.com or bitbucket.org shorthand, or as an scp-like ssh address.
+func parseGitSource(source string) (host string, repoPath string, ref string, ok bool) {
+
+	switch {
+	case strings.HasPrefix(source, "git::"):
+		source = strings.TrimPrefix(source, "git::")
+	case strings.HasPrefix(source, "github.com/"), strings.HasPrefix(source, "bitbucket.org/"):
+		source = "https://" + source
+	case strings.HasPrefix(source, "git@"):
+	default:
+		return "", "", "", false
+	}
+
+	var query string
+	if idx := strings.Index(source, "?"); idx > -1 {
+		query = source[idx+1:]
+		source = source[:idx]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", "", "", false
+	}
+	ref = values.Get("ref")
+
+	if matches := scpLikeGitPattern.FindStringSubmatch(source); matches != nil {
+		return matches[1], matches[2], ref, true
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || u.Host == "" {
+		return "", "", "", false
+	}
+	return u.Hostname(), u.Path, ref, true
+}
+
+func runGit(gitDir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"--git-dir", gitDir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,241 @@
+package parser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+)
+
+// ModuleResolver locates modules which are not available on the local filesystem, such as registry and git modules,
+// without requiring a `terraform init`. A resolver returns the local directory containing the module source, or an
+// empty path if it does not handle sources of the given kind.
+type ModuleResolver interface {
+	ResolveModule(source string, version string) (string, error)
+}
+
+type moduleResolvers []ModuleResolver
+
+// ModuleCache resolves modules from a local cache directory, which can contain any of the following:
+//
+//	registry/<namespace>/<name>/<provider>/<version>/  - a mirror of registry modules
+//	git/<host>/<path>.git                             - bare git repositories, checked out at the requested ?ref=
+//	archives/<host>/<path>                            - module archives (.zip, .tar.gz, .tgz or .tar)
+func ModuleCache(dir string) ModuleResolver {
+	return moduleResolvers{
+		GitRepositories(filepath.Join(dir, "git")),
+		Archives(filepath.Join(dir, "archives")),
+		RegistryMirror(filepath.Join(dir, "registry")),
+	}
+}
+
+func (resolvers moduleResolvers) ResolveModule(source string, version string) (string, error) {
+	for _, resolver := range resolvers {
+		path, err := resolver.ResolveModule(source, version)
+		if err != nil {
+			return "", err
+		}
+		if path != "" {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// splitSubdir separates the subdirectory of a module source (e.g. the "modules/x" in "org/name/aws//modules/x"),
+// keeping any query string on the returned source
+func splitSubdir(source string) (string, string) {
+	offset := 0
+	if idx := strings.Index(source, "://"); idx > -1 {
+		offset = idx + 3
+	}
+	idx := strings.Index(source[offset:], "//")
+	if idx == -1 {
+		return source, ""
+	}
+	idx += offset
+	subdir := source[idx+2:]
+	source = source[:idx]
+	if query := strings.Index(subdir, "?"); query > -1 {
+		source += subdir[query:]
+		subdir = subdir[:query]
+	}
+	return source, subdir
+}
+
+// extractedModulesDir is the directory modules are extracted to when there is no user cache directory. It is created
+// afresh for each run, so nothing in it is reused.
+var extractedModulesDir struct {
+	once sync.Once
+	path string
+	err  error
+}
+
+// extractedModulesRoot returns the directory extracted modules are kept in, which is in the user's cache directory, so
+// that other users cannot plant modules in it
+func extractedModulesRoot() (string, error) {
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		root := filepath.Join(cacheDir, "tfsec", "modules")
+		if err := os.MkdirAll(root, 0700); err != nil {
+			return "", err
+		}
+		if err := checkPrivateDir(root); err != nil {
+			return "", err
+		}
+		return root, nil
+	}
+	extractedModulesDir.once.Do(func() {
+		extractedModulesDir.path, extractedModulesDir.err = ioutil.TempDir(os.TempDir(), "tfsec-modules-")
+	})
+	return extractedModulesDir.path, extractedModulesDir.err
+}
+
+// checkPrivateDir returns an error if other users could write to the directory, as anything in it could then have been
+// planted there
+func checkPrivateDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("module cache '%s' is not a directory", dir)
+	}
+	if info.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("module cache '%s' can be written to by other users, refusing to use it", dir)
+	}
+	return nil
+}
+
+// extractedModuleDir returns a directory containing the contents written by extract. Extracted modules are kept in
+// the user's cache directory under the given key, which must identify the content exactly (e.g. a commit hash), so
+// later scans can reuse them.
+func extractedModuleDir(key string, extract func(dir string) error) (string, error) {
+
+	root, err := extractedModulesRoot()
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(root, key)
+	if _, err := os.Stat(target); err == nil {
+		debug.Log("Using previously extracted module at %s", target)
+		return target, nil
+	}
+
+	tmp, err := ioutil.TempDir(filepath.Dir(target), key+"-")
+	if err != nil {
+		return "", err
+	}
+
+	if err := extract(tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.RemoveAll(tmp)
+		// another scan may have extracted the same module in the meantime
+		if _, statErr := os.Stat(target); statErr == nil {
+			return target, nil
+		}
+		return "", err
+	}
+
+	debug.Log("Extracted module to %s", target)
+	return target, nil
+}
+
+// extractPath returns the path within dir for an archive entry, refusing entries which would be written outside it
+func extractPath(dir string, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	if path != filepath.Clean(dir) && !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry '%s' is outside of the archive", name)
+	}
+	return path, nil
+}
+
+func extractTar(r io.Reader, dir string) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path, err := extractPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := writeExtractedFile(path, reader); err != nil {
+				return err
+			}
+		default:
+			debug.Log("Skipping archive entry '%s' which is not a regular file", header.Name)
+		}
+	}
+}
+
+func extractZip(filename string, dir string) error {
+	reader, err := zip.OpenReader(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	for _, file := range reader.File {
+		path, err := extractPath(dir, file.Name)
+		if err != nil {
+			return err
+		}
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			debug.Log("Skipping archive entry '%s' which is not a regular file", file.Name)
+			continue
+		}
+		if err := func() error {
+			r, err := file.Open()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = r.Close() }()
+			return writeExtractedFile(path, r)
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeExtractedFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
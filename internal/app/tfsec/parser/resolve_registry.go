@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+)
+
+const defaultRegistryHost = "registry.terraform.io"
+
+var registryNamePattern = regexp.MustCompile(`^[0-9A-Za-z](?:[0-9A-Za-z-_]{0,62}[0-9A-Za-z])?$`)
+
+type registryMirror string
+
+// RegistryMirror resolves registry modules (e.g. "hashicorp/consul/aws") from a directory laid out as
+// <namespace>/<name>/<provider>/<version>. Modules from registries other than the public registry are found under a
+// directory named for the registry host. The highest version which satisfies the module's version constraint is used.
+func RegistryMirror(dir string) ModuleResolver {
+	return registryMirror(dir)
+}
+
+func (r registryMirror) ResolveModule(source string, constraint string) (string, error) {
+
+	source, subdir := splitSubdir(source)
+
+	parts := strings.Split(source, "/")
+	host := defaultRegistryHost
+	if len(parts) == 4 {
+		host = parts[0]
+		parts = parts[1:]
+	}
+	if len(parts) != 3 || !strings.Contains(host, ".") {
+		return "", nil
+	}
+	for _, part := range parts {
+		if !registryNamePattern.MatchString(part) {
+			return "", nil
+		}
+	}
+
+	moduleDir := filepath.Join(append([]string{string(r)}, parts...)...)
+	if host != defaultRegistryHost {
+		moduleDir = filepath.Join(append([]string{string(r), host}, parts...)...)
+	}
+
+	entries, err := ioutil.ReadDir(moduleDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("registry module '%s' is not in the module cache at %s", source, moduleDir)
+		}
+		return "", err
+	}
+
+	var constraints version.Constraints
+	if constraint != "" {
+		constraints, err = version.NewConstraint(constraint)
+		if err != nil {
+			return "", fmt.Errorf("invalid version constraint '%s' for module '%s': %w", constraint, source, err)
+		}
+	}
+
+	var best *version.Version
+	var bestDir string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		v, err := version.NewVersion(entry.Name())
+		if err != nil {
+			continue
+		}
+		if constraints != nil && !constraints.Check(v) {
+			continue
+		}
+		if best == nil || v.GreaterThan(best) {
+			best = v
+			bestDir = entry.Name()
+		}
+	}
+
+	if best == nil {
+		return "", fmt.Errorf("no version of registry module '%s' in the module cache matches '%s'", source, constraint)
+	}
+
+	debug.Log("Resolved registry module '%s' to version %s", source, best)
+	return filepath.Join(moduleDir, bestDir, subdir), nil
+}
@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+	"github.com/tfsec/tfsec/internal/app/tfsec/metrics"
+)
+
+var rootModuleSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "backend",
+			LabelNames: []string{"type"},
+		},
+		{
+			Type: "cloud",
+		},
+	},
+}
+
+var moduleSourceSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "source",
+		},
+	},
+}
+
+// FindRootModules returns every directory below the initial path which is a root module, so that each can be parsed as
+// a project of its own. A directory of terraform files is a root module if it configures a backend or a provider, or if
+// no other directory calls it as a local module. Terragrunt units which deploy a terraform source are root modules, and
+// the source they deploy is not. Hidden directories, such as .terraform and .terragrunt-cache, are not searched.
+func (parser *Parser) FindRootModules() ([]string, error) {
+
+	t := metrics.Start(metrics.DiskIO)
+	var dirs []string
+	var units []string
+	var paths []string
+	var dirFileCounts []int
+	err := filepath.Walk(parser.initialPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != parser.initialPath && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if hasTerragruntConfig(path) {
+			units = append(units, path)
+		}
+		dirPaths, err := terraformFiles(path)
+		if err != nil {
+			return err
+		}
+		if len(dirPaths) > 0 {
+			dirs = append(dirs, path)
+			paths = append(paths, dirPaths...)
+			dirFileCounts = append(dirFileCounts, len(dirPaths))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+
+	// errors are ignored here, as they are reported when each root module is parsed
+	files, _, err := parseFiles(paths, parser.parallelism, false)
+	if err != nil {
+		return nil, err
+	}
+
+	configured := make(map[string]bool)
+	called := make(map[string]bool)
+	for i, dir := range dirs {
+		blocks, _, err := loadBlocksFromFiles(files[:dirFileCounts[i]], false)
+		if err != nil {
+			return nil, err
+		}
+		files = files[dirFileCounts[i]:]
+		for _, b := range blocks {
+			switch b.Type {
+			case "provider":
+				configured[dir] = true
+			case "terraform":
+				if content, _, _ := b.Body.PartialContent(rootModuleSchema); content != nil && len(content.Blocks) > 0 {
+					configured[dir] = true
+				}
+			case "module":
+				if source := localModuleSource(b); source != "" {
+					called[filepath.Join(dir, source)] = true
+				}
+			}
+		}
+	}
+
+	isRoot := make(map[string]bool)
+	for _, unit := range units {
+		config, _, err := loadTerragruntConfig(unit)
+		if err != nil {
+			// the unit is still scanned, so that the error is reported
+			debug.Log("Failed to read terragrunt configuration in %s: %s", unit, err)
+			isRoot[unit] = true
+			continue
+		}
+		if config.source == "" {
+			// without a source, a unit deploys its own terraform files, or is only configuration for other units
+			continue
+		}
+		isRoot[unit] = true
+		if source, err := resolveTerragruntSource(unit, config.source, parser.moduleResolver); err == nil {
+			called[source] = true
+		}
+	}
+	for _, dir := range dirs {
+		if configured[dir] || !called[dir] {
+			isRoot[dir] = true
+		}
+	}
+
+	var roots []string
+	for root := range isRoot {
+		debug.Log("Found root module: %s", root)
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	return roots, nil
+}
+
+// IsTerragruntRepository returns true for a directory of terragrunt units, such as a terragrunt live repository, which
+// has no terraform configuration of its own
+func IsTerragruntRepository(dir string) bool {
+	if paths, err := terraformFiles(dir); err != nil || len(paths) > 0 {
+		return false
+	}
+	if hasTerragruntConfig(dir) {
+		if config, _, err := loadTerragruntConfig(dir); err != nil || config.source != "" {
+			return false
+		}
+	}
+	found := false
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || found {
+			return filepath.SkipDir
+		}
+		if info.IsDir() && path != dir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && info.Name() == terragruntConfigFile && path != filepath.Join(dir, terragruntConfigFile) {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// localModuleSource returns the source of a module block which is a path on the local file system, or an empty string
+// for any other source
+func localModuleSource(b *hcl.Block) string {
+	content, _, _ := b.Body.PartialContent(moduleSourceSchema)
+	if content == nil {
+		return ""
+	}
+	attr, exists := content.Attributes["source"]
+	if !exists {
+		return ""
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.Type() != cty.String || !value.IsKnown() || value.IsNull() {
+		return ""
+	}
+	source := value.AsString()
+	if !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") {
+		return ""
+	}
+	return source
+}
@@ -0,0 +1,271 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// typeDefaults holds the defaults given to the optional attributes of an object type, and to those of the object types
+// nested within it, so that they can be filled in once a value has been converted to the type
+type typeDefaults struct {
+	values   map[string]cty.Value     // defaults of optional attributes, by attribute name
+	children map[string]*typeDefaults // defaults of nested types, by attribute name, tuple index, or "" for collection elements
+}
+
+// typeConstraint reads the type constraint of a variable, including the optional attributes of object types and their
+// defaults. The constraint keywords of terraform 0.11, such as "string" and "list", are also supported.
+func typeConstraint(expr hcl.Expression) (cty.Type, *typeDefaults, hcl.Diagnostics) {
+	if _, native := expr.(hclsyntax.Expression); !native {
+		// constraints are written as strings in JSON configuration
+		val, diags := expr.Value(nil)
+		if diags.HasErrors() || val.Type() != cty.String || !val.IsKnown() || val.IsNull() {
+			return cty.NilType, nil, typeDiagnostics(expr, "A type constraint must be given as a string.")
+		}
+		rng := expr.Range()
+		parsed, parseDiags := hclsyntax.ParseExpression([]byte(val.AsString()), rng.Filename, rng.Start)
+		if parseDiags.HasErrors() {
+			return cty.NilType, nil, parseDiags
+		}
+		expr = parsed
+	}
+	if val, diags := expr.Value(nil); !diags.HasErrors() && val.Type() == cty.String && val.IsKnown() && !val.IsNull() {
+		switch val.AsString() {
+		case "string":
+			return cty.String, nil, nil
+		case "list":
+			return cty.List(cty.DynamicPseudoType), nil, nil
+		case "map":
+			return cty.Map(cty.DynamicPseudoType), nil, nil
+		}
+	}
+	return readType(expr)
+}
+
+func readType(expr hcl.Expression) (cty.Type, *typeDefaults, hcl.Diagnostics) {
+	switch hcl.ExprAsKeyword(expr) {
+	case "string":
+		return cty.String, nil, nil
+	case "number":
+		return cty.Number, nil, nil
+	case "bool":
+		return cty.Bool, nil, nil
+	case "any":
+		return cty.DynamicPseudoType, nil, nil
+	case "list":
+		return cty.List(cty.DynamicPseudoType), nil, nil
+	case "set":
+		return cty.Set(cty.DynamicPseudoType), nil, nil
+	case "map":
+		return cty.Map(cty.DynamicPseudoType), nil, nil
+	}
+
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() {
+		return cty.NilType, nil, typeDiagnostics(expr, "A type constraint must be a type keyword or a type constructor such as list(string).")
+	}
+	if len(call.Arguments) != 1 && call.Name != "optional" {
+		return cty.NilType, nil, typeDiagnostics(expr, fmt.Sprintf("The %s type constructor requires one argument.", call.Name))
+	}
+
+	switch call.Name {
+	case "list", "set", "map":
+		elementType, elementDefaults, diags := readType(call.Arguments[0])
+		if diags.HasErrors() {
+			return cty.NilType, nil, diags
+		}
+		var defaults *typeDefaults
+		if elementDefaults != nil {
+			defaults = &typeDefaults{children: map[string]*typeDefaults{"": elementDefaults}}
+		}
+		switch call.Name {
+		case "list":
+			return cty.List(elementType), defaults, nil
+		case "set":
+			return cty.Set(elementType), defaults, nil
+		default:
+			return cty.Map(elementType), defaults, nil
+		}
+	case "tuple":
+		elements, diags := hcl.ExprList(call.Arguments[0])
+		if diags.HasErrors() {
+			return cty.NilType, nil, typeDiagnostics(call.Arguments[0], "The tuple type constructor requires a list of element types.")
+		}
+		types := make([]cty.Type, len(elements))
+		defaults := &typeDefaults{children: make(map[string]*typeDefaults)}
+		for i, element := range elements {
+			elementType, elementDefaults, diags := readType(element)
+			if diags.HasErrors() {
+				return cty.NilType, nil, diags
+			}
+			types[i] = elementType
+			if elementDefaults != nil {
+				defaults.children[strconv.Itoa(i)] = elementDefaults
+			}
+		}
+		if len(defaults.children) == 0 {
+			defaults = nil
+		}
+		return cty.Tuple(types), defaults, nil
+	case "object":
+		return readObjectType(call.Arguments[0])
+	case "optional":
+		return cty.NilType, nil, typeDiagnostics(expr, "Optional attributes can only be declared within an object type.")
+	}
+	return cty.NilType, nil, typeDiagnostics(expr, fmt.Sprintf("The type constructor %s is not supported.", call.Name))
+}
+
+func readObjectType(expr hcl.Expression) (cty.Type, *typeDefaults, hcl.Diagnostics) {
+	pairs, diags := hcl.ExprMap(expr)
+	if diags.HasErrors() {
+		return cty.NilType, nil, typeDiagnostics(expr, "The object type constructor requires an object of attribute types.")
+	}
+	attributes := make(map[string]cty.Type)
+	var optional []string
+	defaults := &typeDefaults{
+		values:   make(map[string]cty.Value),
+		children: make(map[string]*typeDefaults),
+	}
+	for _, pair := range pairs {
+		name := hcl.ExprAsKeyword(pair.Key)
+		if name == "" {
+			key, diags := pair.Key.Value(nil)
+			if diags.HasErrors() || key.Type() != cty.String || !key.IsKnown() || key.IsNull() {
+				return cty.NilType, nil, typeDiagnostics(pair.Key, "Object attribute names must be static strings.")
+			}
+			name = key.AsString()
+		}
+
+		typeExpr := pair.Value
+		var defaultExpr hcl.Expression
+		if call, diags := hcl.ExprCall(typeExpr); !diags.HasErrors() && call.Name == "optional" {
+			if len(call.Arguments) < 1 || len(call.Arguments) > 2 {
+				return cty.NilType, nil, typeDiagnostics(typeExpr, "The optional modifier requires a type, and may be given a default.")
+			}
+			optional = append(optional, name)
+			typeExpr = call.Arguments[0]
+			if len(call.Arguments) == 2 {
+				defaultExpr = call.Arguments[1]
+			}
+		}
+
+		attributeType, attributeDefaults, diags := readType(typeExpr)
+		if diags.HasErrors() {
+			return cty.NilType, nil, diags
+		}
+		attributes[name] = attributeType
+		if attributeDefaults != nil {
+			defaults.children[name] = attributeDefaults
+		}
+		if defaultExpr != nil {
+			def, diags := defaultExpr.Value(nil)
+			if diags.HasErrors() {
+				return cty.NilType, nil, diags
+			}
+			converted, err := convert.Convert(def, attributeType)
+			if err != nil {
+				return cty.NilType, nil, typeDiagnostics(defaultExpr, fmt.Sprintf("The default of the optional attribute %q is not valid: %s.", name, err))
+			}
+			// the default is itself given the defaults of any optional attributes it omits
+			defaults.values[name] = attributeDefaults.apply(converted)
+		}
+	}
+	if len(defaults.values) == 0 && len(defaults.children) == 0 {
+		defaults = nil
+	}
+	if len(optional) == 0 {
+		return cty.Object(attributes), defaults, nil
+	}
+	return cty.ObjectWithOptionalAttrs(attributes, optional), defaults, nil
+}
+
+func typeDiagnostics(expr hcl.Expression, detail string) hcl.Diagnostics {
+	rng := expr.Range()
+	return hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid type constraint",
+			Detail:   detail,
+			Subject:  &rng,
+		},
+	}
+}
+
+// apply fills in the defaults of optional attributes which were not given, in a value which has already been converted
+// to the type the defaults were read with
+func (d *typeDefaults) apply(val cty.Value) cty.Value {
+	if d == nil || val.IsNull() || !val.IsKnown() {
+		return val
+	}
+	ty := val.Type()
+	switch {
+	case ty.IsObjectType():
+		attributes := val.AsValueMap()
+		if attributes == nil {
+			return val
+		}
+		for name, def := range d.values {
+			if current, exists := attributes[name]; exists && current.IsNull() {
+				if converted, err := convert.Convert(def, ty.AttributeType(name)); err == nil {
+					attributes[name] = converted
+				}
+			}
+		}
+		for name, child := range d.children {
+			if current, exists := attributes[name]; exists {
+				attributes[name] = child.apply(current)
+			}
+		}
+		return cty.ObjectVal(attributes)
+	case ty.IsTupleType():
+		elements := val.AsValueSlice()
+		for i := range elements {
+			elements[i] = d.children[strconv.Itoa(i)].apply(elements[i])
+		}
+		return cty.TupleVal(elements)
+	case ty.IsListType(), ty.IsSetType():
+		if val.LengthInt() == 0 {
+			return val
+		}
+		elements := val.AsValueSlice()
+		for i := range elements {
+			elements[i] = d.children[""].apply(elements[i])
+		}
+		if !sameTypes(elements) {
+			// an attribute of type any was given a default of another type, so a list can no longer hold the elements
+			return cty.TupleVal(elements)
+		}
+		if ty.IsSetType() {
+			return cty.SetVal(elements)
+		}
+		return cty.ListVal(elements)
+	case ty.IsMapType():
+		if val.LengthInt() == 0 {
+			return val
+		}
+		elements := val.AsValueMap()
+		var values []cty.Value
+		for key, element := range elements {
+			elements[key] = d.children[""].apply(element)
+			values = append(values, elements[key])
+		}
+		if !sameTypes(values) {
+			return cty.ObjectVal(elements)
+		}
+		return cty.MapVal(elements)
+	}
+	return val
+}
+
+func sameTypes(values []cty.Value) bool {
+	for _, val := range values[1:] {
+		if !val.Type().Equals(values[0].Type()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package parser
+
+import "sync"
+
+// runInParallel calls fn with each index from 0 to count-1, running up to parallelism calls at a time. Callers store
+// their results by index, so that results are in the same order regardless of which calls finish first. Likewise, the
+// error returned is that of the lowest index which failed.
+func runInParallel(parallelism int, count int, fn func(i int) error) error {
+
+	if parallelism <= 1 {
+		for i := 0; i < count; i++ {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, count)
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
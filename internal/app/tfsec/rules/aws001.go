@@ -54,7 +54,12 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_s3_bucket"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -74,6 +79,8 @@ func init() {
 							WithRange(attr.Range()).
 							WithSeverity(severity.Warning),
 					)
+				} else if !attr.IsResolvable() {
+					set.Add(unresolvedResult(block, attr).WithSeverity(severity.Warning))
 				}
 			}
 		},
@@ -52,7 +52,12 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "10.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_s3_bucket"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -50,9 +50,11 @@ func init() {
 				"https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/ec2-classic-platform.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_db_security_group", "aws_redshift_security_group", "aws_elasticache_security_group"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_db_security_group", "aws_redshift_security_group", "aws_elasticache_security_group"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 			set.Add(
 				result.New().
@@ -51,7 +51,12 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/lb",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_alb", "aws_elb", "aws_lb"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -53,7 +53,13 @@ func init() {
 				"https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/security-group-rules-reference.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "5.2"},
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_security_group_rule"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -75,6 +81,8 @@ func init() {
 							WithRange(cidrBlocksAttr.Range()).
 							WithSeverity(severity.Warning),
 					)
+				} else if !cidrBlocksAttr.IsResolvable() {
+					set.Add(unresolvedResult(block, cidrBlocksAttr).WithSeverity(severity.Warning))
 				}
 			}
 
@@ -87,6 +95,8 @@ func init() {
 							WithAttributeAnnotation(ipv6CidrBlocksAttr).
 							WithSeverity(severity.Warning),
 					)
+				} else if !ipv6CidrBlocksAttr.IsResolvable() {
+					set.Add(unresolvedResult(block, ipv6CidrBlocksAttr).WithSeverity(severity.Warning))
 				}
 
 			}
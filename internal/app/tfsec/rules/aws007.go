@@ -53,7 +53,12 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/security_group_rule",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.2.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_security_group_rule"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -77,6 +82,8 @@ func init() {
 							WithAttributeAnnotation(cidrBlocksAttr).
 							WithSeverity(severity.Warning),
 					)
+				} else if !cidrBlocksAttr.IsResolvable() {
+					set.Add(unresolvedResult(block, cidrBlocksAttr).WithSeverity(severity.Warning))
 				}
 			}
 
@@ -90,6 +97,8 @@ func init() {
 							WithAttributeAnnotation(ipv6CidrBlocksAttr).
 							WithSeverity(severity.Warning),
 					)
+				} else if !ipv6CidrBlocksAttr.IsResolvable() {
+					set.Add(unresolvedResult(block, ipv6CidrBlocksAttr).WithSeverity(severity.Warning))
 				}
 			}
 		},
@@ -53,7 +53,13 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/security_group",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "5.2"},
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_security_group"},
 		CheckFunc: func(resultSet result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -69,6 +75,8 @@ func init() {
 								WithAttributeAnnotation(cidrBlocksAttr).
 								WithSeverity(severity.Warning),
 						)
+					} else if !cidrBlocksAttr.IsResolvable() {
+						resultSet.Add(unresolvedResult(block, cidrBlocksAttr).WithSeverity(severity.Warning))
 					}
 				}
 
@@ -81,6 +89,8 @@ func init() {
 								WithRange(cidrBlocksAttr.Range()).
 								WithSeverity(severity.Warning),
 						)
+					} else if !cidrBlocksAttr.IsResolvable() {
+						resultSet.Add(unresolvedResult(block, cidrBlocksAttr).WithSeverity(severity.Warning))
 					}
 				}
 			}
@@ -53,7 +53,12 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/security_group",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.2.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_security_group"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -69,6 +74,8 @@ func init() {
 								WithAttributeAnnotation(cidrBlocksAttr).
 								WithSeverity(severity.Warning),
 						)
+					} else if !cidrBlocksAttr.IsResolvable() {
+						set.Add(unresolvedResult(block, cidrBlocksAttr).WithSeverity(severity.Warning))
 					}
 				}
 
@@ -82,6 +89,8 @@ func init() {
 								WithAttributeAnnotation(cidrBlocksAttr).
 								WithSeverity(severity.Warning),
 						)
+					} else if !cidrBlocksAttr.IsResolvable() {
+						set.Add(unresolvedResult(block, cidrBlocksAttr).WithSeverity(severity.Warning))
 					}
 				}
 			}
@@ -61,7 +61,12 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/lb_listener",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption, rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "4.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_lb_listener", "aws_alb_listener"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -53,7 +53,12 @@ func init() {
 				"https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/using-instance-addressing.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_launch_configuration", "aws_instance"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -80,7 +80,12 @@ func init() {
 				"https://www.vaultproject.io/",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "8.2.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_ecs_task_definition"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -56,7 +56,12 @@ func init() {
 				"https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/RootDeviceStorage.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_launch_configuration"},
 		CheckFunc: func(set result.Set, block *block.Block, context *hclcontext.Context) {
@@ -52,7 +52,12 @@ func init() {
 				"https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-server-side-encryption.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_sqs_queue"},
 		CheckFunc: func(set result.Set, block *block.Block, context *hclcontext.Context) {
@@ -53,7 +53,12 @@ func init() {
 				"https://docs.aws.amazon.com/sns/latest/dg/sns-server-side-encryption.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_sns_topic"},
 		CheckFunc: func(set result.Set, block *block.Block, ctx *hclcontext.Context) {
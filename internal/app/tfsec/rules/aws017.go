@@ -59,7 +59,13 @@ func init() {
 				"https://docs.aws.amazon.com/AmazonS3/latest/userguide/bucket-encryption.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "2.1.1"},
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_s3_bucket"},
 		CheckFunc: func(set result.Set, block *block.Block, context *hclcontext.Context) {
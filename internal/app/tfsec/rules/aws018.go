@@ -71,9 +71,11 @@ func init() {
 				"https://www.cloudconformity.com/knowledge-base/aws/EC2/security-group-rules-description.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_security_group", "aws_security_group_rule"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_security_group", "aws_security_group_rule"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 			if block.MissingChild("description") {
 				set.Add(
@@ -53,7 +53,13 @@ func init() {
 				"https://docs.aws.amazon.com/kms/latest/developerguide/rotate-keys.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "3.8"},
+			{Framework: rule.PCIDSS321, ID: "3.6.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_kms_key"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
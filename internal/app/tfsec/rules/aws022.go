@@ -61,7 +61,12 @@ func init() {
 				"https://docs.aws.amazon.com/msk/latest/developerguide/msk-encryption.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "4.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_msk_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, context *hclcontext.Context) {
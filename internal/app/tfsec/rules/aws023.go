@@ -63,9 +63,11 @@ func init() {
 				"https://docs.aws.amazon.com/AmazonECR/latest/userguide/image-scanning.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_ecr_repository"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagHardening},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_ecr_repository"},
 		CheckFunc: func(set result.Set, block *block.Block, context *hclcontext.Context) {
 
 			ecrScanStatusBlock := block.GetBlock("image_scanning_configuration")
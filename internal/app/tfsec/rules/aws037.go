@@ -61,7 +61,13 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/iam_account_password_policy",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "1.9"},
+			{Framework: rule.PCIDSS321, ID: "8.2.5"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_iam_account_password_policy"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -52,7 +52,12 @@ func init() {
 				"https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-files.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "8.2.1"},
+		},
 		RequiredTypes:  []string{"provider"},
 		RequiredLabels: []string{"aws"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -106,7 +106,12 @@ func init() {
 				"https://docs.aws.amazon.com/waf/latest/developerguide/cloudfront-features.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "6.6"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_cloudfront_distribution"},
 		CheckFunc: func(set result.Set, block *block.Block, context *hclcontext.Context) {
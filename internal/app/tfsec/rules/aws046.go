@@ -64,7 +64,13 @@ func init() {
 			GoodExample: AWSIamPolicyWildcardActionsGoodExample,
 			Links:       []string{},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "1.16"},
+			{Framework: rule.PCIDSS321, ID: "7.1.2"},
+		},
 		RequiredTypes:  []string{"data"},
 		RequiredLabels: []string{"aws_iam_policy_document"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
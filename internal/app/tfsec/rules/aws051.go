@@ -53,7 +53,13 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/rds_cluster",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "2.3.1"},
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_rds_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -59,9 +59,11 @@ func init() {
 				"https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-lambda-permission.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_lambda_permission"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagIAM},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_lambda_permission"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			if block.HasChild("principal") {
@@ -74,7 +74,12 @@ func init() {
 				"https://docs.aws.amazon.com/apigateway/latest/developerguide/set-up-logging.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "10.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_apigatewayv2_stage", "aws_api_gateway_stage"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
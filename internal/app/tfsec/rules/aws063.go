@@ -68,7 +68,13 @@ func init() {
 				"https://docs.aws.amazon.com/awscloudtrail/latest/userguide/receive-cloudtrail-log-files-from-multiple-regions.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "3.1"},
+			{Framework: rule.PCIDSS321, ID: "10.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_cloudtrail"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -72,7 +72,13 @@ func init() {
 				"https://docs.aws.amazon.com/awscloudtrail/latest/userguide/encrypting-cloudtrail-log-files-with-aws-kms.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption, rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "3.7"},
+			{Framework: rule.PCIDSS321, ID: "10.5"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_cloudtrail"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
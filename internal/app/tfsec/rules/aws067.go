@@ -74,7 +74,12 @@ func init() {
 				"https://docs.aws.amazon.com/eks/latest/userguide/control-plane-logs.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "10.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_eks_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
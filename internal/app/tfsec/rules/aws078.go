@@ -62,9 +62,11 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/ecr_repository",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_ecr_repository"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagHardening},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_ecr_repository"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			imageTagMutabilityAttr := block.GetAttribute("image_tag_mutability")
@@ -57,9 +57,11 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/instance#metadata-options",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_instance"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagHardening},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_instance"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			metaDataOptions := block.GetBlock("metadata_options")
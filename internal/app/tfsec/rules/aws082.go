@@ -50,9 +50,11 @@ func init() {
 				"https://docs.aws.amazon.com/vpc/latest/userguide/default-vpc.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_default_vpc"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_default_vpc"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 			set.Add(
 				result.New().
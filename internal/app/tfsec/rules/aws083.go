@@ -72,9 +72,11 @@ func init() {
 				"https://docs.aws.amazon.com/elasticloadbalancing/latest/application/application-load-balancers.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_alb", "aws_lb"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_alb", "aws_lb"},
 		CheckFunc: func(set result.Set, b *block.Block, _ *hclcontext.Context) {
 
 			if b.GetAttribute("load_balancer_type").Equals("application", block.IgnoreCase) {
@@ -72,7 +72,12 @@ func init() {
 				"https://docs.aws.amazon.com/workspaces/latest/adminguide/encrypt-workspaces.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_workspaces_workspace"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -62,7 +62,12 @@ func init() {
 				"https://docs.aws.amazon.com/config/latest/developerguide/aggregate-data.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAWS14, ID: "3.5"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_config_configuration_aggregator"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
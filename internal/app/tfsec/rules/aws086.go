@@ -74,9 +74,11 @@ func init() {
 				"https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/PointInTimeRecovery.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_dynamodb_table"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagBackup},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_dynamodb_table"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			if block.MissingChild("point_in_time_recovery") {
@@ -64,7 +64,12 @@ func init() {
 				"https://docs.aws.amazon.com/redshift/latest/mgmt/managing-clusters-vpc.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_redshift_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -64,9 +64,11 @@ func init() {
 				"https://docs.aws.amazon.com/AmazonElastiCache/latest/red-ug/backups-automatic.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_elasticache_cluster"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagBackup},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_elasticache_cluster"},
 		CheckFunc: func(set result.Set, b *block.Block, _ *hclcontext.Context) {
 
 			engineAttr := b.GetAttribute("engine")
@@ -55,9 +55,11 @@ func init() {
 				"https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/ContainerInsights.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_ecs_cluster"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Info,
+		Tags:            []rule.Tag{rule.TagLogging},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_ecs_cluster"},
 		CheckFunc: func(set result.Set, resourceBlock *block.Block, _ *hclcontext.Context) {
 
 			settingsBlock := resourceBlock.GetBlocks("setting")
@@ -91,9 +91,11 @@ func init() {
 				"https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_WorkingWithAutomatedBackups.html#USER_WorkingWithAutomatedBackups.BackupRetention",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_rds_cluster", "aws_db_instance"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Info,
+		Tags:            []rule.Tag{rule.TagBackup},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_rds_cluster", "aws_db_instance"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 			if block.HasChild("replicate_source_db") {
 				return
@@ -93,9 +93,11 @@ func init() {
 				"https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/EncryptionAtRest.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_dynamodb_table"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Info,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_dynamodb_table"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			if block.MissingChild("server_side_encryption") {
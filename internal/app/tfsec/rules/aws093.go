@@ -70,9 +70,11 @@ func init() {
 				"https://docs.aws.amazon.com/AmazonECR/latest/userguide/encryption-at-rest.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_ecr_repository"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Info,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_ecr_repository"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			if block.MissingChild("encryption_configuration") {
@@ -66,7 +66,12 @@ func init() {
 				"https://docs.aws.amazon.com/redshift/latest/mgmt/working-with-db-encryption.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_redshift_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
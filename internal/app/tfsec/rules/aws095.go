@@ -56,9 +56,11 @@ func init() {
 				"https://docs.aws.amazon.com/kms/latest/developerguide/services-secrets-manager.html#asm-encrypt",
 			},
 		},
-		Provider:       provider.AWSProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"aws_secretsmanager_secret"},
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Info,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"aws_secretsmanager_secret"},
 		CheckFunc: func(set result.Set, block *block.Block, ctx *hclcontext.Context) {
 
 			if block.MissingChild("kms_key_id") {
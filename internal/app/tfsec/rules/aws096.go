@@ -81,7 +81,12 @@ func init() {
 				"https://docs.aws.amazon.com/efs/latest/ug/encryption-in-transit.html",
 			},
 		},
-		Provider:       provider.AWSProvider,
+		Provider:        provider.AWSProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "4.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"aws_ecs_task_definition"},
 		CheckFunc: func(set result.Set, b *block.Block, _ *hclcontext.Context) {
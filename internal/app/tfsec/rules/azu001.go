@@ -58,7 +58,14 @@ func init() {
 				"https://www.terraform.io/docs/providers/azurerm/r/network_security_rule.html",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "6.1"},
+			{Framework: rule.CISAzure13, ID: "6.2"},
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_network_security_rule"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
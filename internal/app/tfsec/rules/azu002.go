@@ -57,7 +57,12 @@ func init() {
 				"https://www.terraform.io/docs/providers/azurerm/r/network_security_rule.html",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.2.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_network_security_rule"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -53,7 +53,12 @@ func init() {
 				"https://www.terraform.io/docs/providers/azurerm/r/managed_disk.html",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_managed_disk"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -51,7 +51,12 @@ func init() {
 				"https://www.terraform.io/docs/providers/azurerm/r/data_lake_store.html",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_data_lake_store"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
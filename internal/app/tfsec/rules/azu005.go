@@ -55,9 +55,11 @@ func init() {
 				"https://www.terraform.io/docs/providers/azurerm/r/virtual_machine.html",
 			},
 		},
-		Provider:       provider.AzureProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"azurerm_virtual_machine"},
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagIAM},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"azurerm_virtual_machine"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			if linuxConfigBlock := block.GetBlock("os_profile_linux_config"); linuxConfigBlock != nil {
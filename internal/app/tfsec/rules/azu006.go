@@ -54,9 +54,11 @@ func init() {
 				"https://kubernetes.io/docs/concepts/services-networking/network-policies",
 			},
 		},
-		Provider:       provider.AzureProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"azurerm_kubernetes_cluster"},
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"azurerm_kubernetes_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			if networkProfileBlock := block.GetBlock("network_profile"); networkProfileBlock != nil {
@@ -55,7 +55,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/aks/concepts-identity",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "8.5"},
+			{Framework: rule.PCIDSS321, ID: "7.1.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_kubernetes_cluster", "role_based_access_control"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
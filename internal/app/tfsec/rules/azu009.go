@@ -55,7 +55,12 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/azure-monitor/insights/container-insights-onboard",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "10.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_kubernetes_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
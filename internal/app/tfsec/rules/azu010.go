@@ -51,7 +51,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/storage/blobs/security-recommendations",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption, rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "3.1"},
+			{Framework: rule.PCIDSS321, ID: "4.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_storage_account", "enable_https_traffic_only"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -60,7 +60,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/firewall/rule-processing",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "3.6"},
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_storage_account", "azurerm_storage_account_network_rules"},
 		CheckFunc: func(set result.Set, b *block.Block, _ *hclcontext.Context) {
@@ -64,7 +64,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/storage/common/storage-require-secure-transfer",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "3.1"},
+			{Framework: rule.PCIDSS321, ID: "4.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_storage_account"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
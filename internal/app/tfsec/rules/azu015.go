@@ -59,7 +59,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/storage/common/transport-layer-security-configure-minimum-version",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "3.12"},
+			{Framework: rule.PCIDSS321, ID: "4.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_storage_account"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -67,7 +67,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/azure-sql/database/auditing-overview",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "4.1.1"},
+			{Framework: rule.PCIDSS321, ID: "10.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_sql_server", "azurerm_mssql_server"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
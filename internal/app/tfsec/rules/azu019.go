@@ -69,7 +69,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/azure-sql/database/auditing-overview",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagLogging},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "4.1.3"},
+			{Framework: rule.PCIDSS321, ID: "10.7"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_sql_server", "azurerm_sql_server", "azurerm_mssql_database_extended_auditing_policy"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
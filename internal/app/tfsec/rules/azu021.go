@@ -59,7 +59,12 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/key-vault/general/soft-delete-overview#purge-protection",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "8.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_key_vault"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
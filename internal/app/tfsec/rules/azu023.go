@@ -57,7 +57,12 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/key-vault/secrets/about-secrets",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "8.2"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_key_vault_secret"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
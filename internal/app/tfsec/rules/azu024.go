@@ -94,7 +94,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/bastion/tutorial-create-host-portal",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "6.1"},
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_network_security_group", "azurerm_network_security_rule"},
 		CheckFunc: func(set result.Set, resourceBlock *block.Block, _ *hclcontext.Context) {
@@ -64,7 +64,13 @@ func init() {
 				"https://docs.microsoft.com/en-us/azure/azure-functions/security-concepts",
 			},
 		},
-		Provider:       provider.AzureProvider,
+		Provider:        provider.AzureProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption, rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISAzure13, ID: "9.2"},
+			{Framework: rule.PCIDSS321, ID: "4.1"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"azurerm_function_app"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -63,7 +63,12 @@ func init() {
 				"https://www.terraform.io/docs/providers/google/r/compute_disk.html",
 			},
 		},
-		Provider:       provider.GCPProvider,
+		Provider:        provider.GCPProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagEncryption},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "3.4"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"google_compute_disk"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
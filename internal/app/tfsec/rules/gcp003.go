@@ -51,7 +51,14 @@ func init() {
 				"https://www.terraform.io/docs/providers/google/r/compute_firewall.html",
 			},
 		},
-		Provider:       provider.GCPProvider,
+		Provider:        provider.GCPProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.CISGCP12, ID: "3.6"},
+			{Framework: rule.CISGCP12, ID: "3.7"},
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"google_compute_firewall"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
@@ -57,9 +57,11 @@ func init() {
 				"https://www.terraform.io/docs/providers/google/r/container_cluster.html#enable_legacy_abac",
 			},
 		},
-		Provider:       provider.GCPProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"google_container_cluster"},
+		Provider:        provider.GCPProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagIAM},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"google_container_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			enable_legacy_abac := block.GetAttribute("enable_legacy_abac")
@@ -62,9 +62,11 @@ func init() {
 				"https://www.terraform.io/docs/providers/google/r/container_cluster.html#node_metadata",
 			},
 		},
-		Provider:       provider.GCPProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"google_container_cluster", "google_container_node_pool"},
+		Provider:        provider.GCPProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagHardening},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"google_container_cluster", "google_container_node_pool"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			nodeMetadata := block.GetBlock("node_config").GetBlock("workload_metadata_config").GetAttribute("node_metadata")
@@ -61,9 +61,11 @@ func init() {
 				"https://www.terraform.io/docs/providers/google/r/container_cluster.html#metadata",
 			},
 		},
-		Provider:       provider.GCPProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"google_container_cluster"},
+		Provider:        provider.GCPProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagHardening},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"google_container_cluster"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			legacyMetadataAPI := block.GetBlock("metadata").GetAttribute("disable-legacy-endpoints")
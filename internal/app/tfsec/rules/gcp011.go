@@ -70,8 +70,10 @@ func init() {
 				"https://www.terraform.io/docs/providers/google/d/iam_policy.html#members",
 			},
 		},
-		Provider:      provider.GCPProvider,
-		RequiredTypes: []string{"resource", "data"},
+		Provider:        provider.GCPProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagIAM},
+		RequiredTypes:   []string{"resource", "data"},
 		RequiredLabels: []string{
 			"google_cloud_run_service_iam_binding",
 			"google_cloud_run_service_iam_member",
@@ -67,7 +67,12 @@ func init() {
 				"https://www.terraform.io/docs/state/sensitive-data.html",
 			},
 		},
-		Provider:      provider.GeneralProvider,
+		Provider:        provider.GeneralProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagIAM},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "8.2.1"},
+		},
 		RequiredTypes: []string{"variable"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
@@ -69,9 +69,11 @@ func init() {
 				"https://registry.terraform.io/providers/integrations/github/latest/docs/resources/repository",
 			},
 		},
-		Provider:       provider.GeneralProvider,
-		RequiredTypes:  []string{"resource"},
-		RequiredLabels: []string{"github_repository"},
+		Provider:        provider.GeneralProvider,
+		DefaultSeverity: severity.Error,
+		Tags:            []rule.Tag{rule.TagIAM},
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"github_repository"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
 
 			privateAttribute := block.GetAttribute("private")
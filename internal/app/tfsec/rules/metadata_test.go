@@ -0,0 +1,23 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+)
+
+func Test_RulesHaveValidMetadata(t *testing.T) {
+	for _, r := range scanner.GetRegisteredRules() {
+		assert.True(t, r.DefaultSeverity.IsValid(), "%s has an invalid default severity '%s'", r.ID, r.DefaultSeverity)
+		assert.NotEmpty(t, r.Tags, "%s has no tags, so it would not be run with --include-tags", r.ID)
+		for _, tag := range r.Tags {
+			assert.True(t, tag.IsValid(), "%s has an unknown tag '%s'", r.ID, tag)
+		}
+		for _, control := range r.Compliance {
+			assert.True(t, control.Framework.IsValid(), "%s is mapped to an unknown framework '%s'", r.ID, control.Framework)
+			assert.NotEmpty(t, control.ID, "%s is mapped to a %s control without an ID", r.ID, control.Framework)
+		}
+	}
+}
@@ -54,7 +54,12 @@ func init() {
 				"https://registry.terraform.io/providers/hashicorp/opc/latest/docs/resources/opc_compute_instance",
 			},
 		},
-		Provider:       provider.OracleProvider,
+		Provider:        provider.OracleProvider,
+		DefaultSeverity: severity.Warning,
+		Tags:            []rule.Tag{rule.TagNetwork},
+		Compliance: []rule.Control{
+			{Framework: rule.PCIDSS321, ID: "1.3"},
+		},
 		RequiredTypes:  []string{"resource"},
 		RequiredLabels: []string{"opc_compute_ip_address_reservation"},
 		CheckFunc: func(set result.Set, block *block.Block, _ *hclcontext.Context) {
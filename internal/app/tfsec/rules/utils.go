@@ -1,9 +1,11 @@
 package rules
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/pkg/result"
 
 	"github.com/zclconf/go-cty/cty"
 )
@@ -51,3 +53,11 @@ func isOpenCidr(attr *block.Attribute) bool {
 
 	return false
 }
+
+// unresolvedResult creates a result for a check which could not be made, as the value of the attribute it depends on is
+// not known until the configuration is applied
+func unresolvedResult(b *block.Block, attr *block.Attribute) *result.Result {
+	return result.New().
+		WithDescription(fmt.Sprintf("Resource '%s' could not be checked, as the value of '%s' is not known.", b.FullName(), attr.Name())).
+		WithUnresolvedAttribute(attr)
+}
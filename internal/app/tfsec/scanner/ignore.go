@@ -0,0 +1,155 @@
+package scanner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/debug"
+)
+
+// ignoreDateFormat is the format of the expiry date of an ignore comment e.g. tfsec:ignore:AWS002:exp:2026-12-31
+const ignoreDateFormat = "2006-01-02"
+
+// ignorePattern matches an ignore comment. The expiry is matched as a date where possible, so that the end of a block
+// comment written straight after it, e.g. /* tfsec:ignore:AWS002:exp:2026-12-31*/, is not taken as part of the date.
+var ignorePattern = regexp.MustCompile(`tfsec:ignore:([^\s:]+)(?::exp:(\d{4}-\d{2}-\d{2}\b|\S+))?`)
+
+// ignore is a tfsec:ignore comment, which suppresses results for a rule, or for every rule if the rule ID is *. An
+// ignore can be given an expiry date, after which it no longer suppresses results, and a justification, which is the
+// text following -- in the comment.
+type ignore struct {
+	ruleID        string
+	expiry        time.Time // zero if the ignore does not expire
+	justification string
+}
+
+// parseIgnores returns the ignores on a line of code. A justification applies to every ignore on the line. Ignores with
+// an expiry which is not a valid date are discarded, so that the results they were meant to suppress are reported.
+func parseIgnores(line string) []ignore {
+	matches := ignorePattern.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var justification string
+	if index := strings.Index(line[matches[0][1]:], "--"); index >= 0 {
+		justification = strings.TrimSpace(line[matches[0][1]+index+2:])
+	}
+
+	var ignores []ignore
+	for _, match := range matches {
+		ign := ignore{
+			ruleID:        line[match[2]:match[3]],
+			justification: justification,
+		}
+		if match[4] >= 0 {
+			expiry, err := time.Parse(ignoreDateFormat, line[match[4]:match[5]])
+			if err != nil {
+				debug.Log("Ignore for '%s' has an invalid expiry date, it will not be used: %s", ign.ruleID, err)
+				continue
+			}
+			ign.expiry = expiry
+		}
+		ignores = append(ignores, ign)
+	}
+	return ignores
+}
+
+func (i ignore) matches(ruleID string) bool {
+	return i.ruleID == "*" || i.ruleID == ruleID
+}
+
+// expired returns true once the expiry date of the ignore has passed. The ignore still applies on the expiry date.
+func (i ignore) expired(now time.Time) bool {
+	if i.expiry.IsZero() {
+		return false
+	}
+	lastDay := time.Date(i.expiry.Year(), i.expiry.Month(), i.expiry.Day(), 0, 0, 0, 0, now.Location())
+	return !now.Before(lastDay.AddDate(0, 0, 1))
+}
+
+// expiryString returns the expiry date in the format it is written in, or an empty string if the ignore does not expire
+func (i ignore) expiryString() string {
+	if i.expiry.IsZero() {
+		return ""
+	}
+	return i.expiry.Format(ignoreDateFormat)
+}
+
+// ignoreLocation identifies an ignore by the line it is written on and the rule it names
+type ignoreLocation struct {
+	filename string
+	line     int
+	ruleID   string
+}
+
+// UnusedIgnore is a tfsec:ignore comment which did not match any result, because the problem it was added for has been
+// fixed, or because it names a rule which does not exist
+type UnusedIgnore struct {
+	RuleID      string      `json:"rule_id"`
+	Range       block.Range `json:"location"`
+	UnknownRule bool        `json:"unknown_rule"`
+}
+
+func (u UnusedIgnore) String() string {
+	if u.UnknownRule {
+		return fmt.Sprintf("%s: tfsec:ignore:%s names a rule which does not exist", u.Range, u.RuleID)
+	}
+	return fmt.Sprintf("%s: tfsec:ignore:%s did not match any result", u.Range, u.RuleID)
+}
+
+// UnusedIgnores returns the ignore comments in the files scanned so far which have not matched any result. A scanner can
+// be used for several scans, such as one for each root module, in which case an ignore is only unused if it matched
+// nothing in any of them. Ignores for rules which were not run, because they were not selected by tag or compliance
+// framework, are not reported.
+func (scanner *Scanner) UnusedIgnores() []UnusedIgnore {
+	rules := GetRegisteredRules()
+	knownRules := make(map[string]bool)
+	for _, r := range rules {
+		knownRules[r.ID] = true
+	}
+	selectedRules := make(map[string]bool)
+	for _, r := range scanner.selectRules(rules) {
+		selectedRules[r.ID] = true
+	}
+
+	var filenames []string
+	for filename := range scanner.scannedFiles {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	var unused []UnusedIgnore
+	for _, filename := range filenames {
+		raw, err := ioutil.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(raw), "\n") {
+			number := i + 1
+			for _, ign := range parseIgnores(line) {
+				if scanner.matchedIgnores[ignoreLocation{filename, number, ign.ruleID}] {
+					continue
+				}
+				if knownRules[ign.ruleID] && !selectedRules[ign.ruleID] {
+					continue
+				}
+				unused = append(unused, UnusedIgnore{
+					RuleID: ign.ruleID,
+					Range: block.Range{
+						Filename:  filename,
+						StartLine: number,
+						EndLine:   number,
+					},
+					UnknownRule: ign.ruleID != "*" && !knownRules[ign.ruleID],
+				})
+			}
+		}
+	}
+	return unused
+}
@@ -1,5 +1,7 @@
 package scanner
 
+import "github.com/tfsec/tfsec/pkg/rule"
+
 type Option func(s *Scanner)
 
 func OptionIncludePassed() func(s *Scanner) {
@@ -19,3 +21,44 @@ func OptionExcludeRules(ruleIDs []string) func(s *Scanner) {
 		s.excludedRuleIDs = ruleIDs
 	}
 }
+
+// OptionIncludeUnresolved includes results for checks which could not be made because a value they depend on is not
+// known, such as a value read from a data source or a variable which has not been set
+func OptionIncludeUnresolved() func(s *Scanner) {
+	return func(s *Scanner) {
+		s.includeUnresolved = true
+	}
+}
+
+// OptionRequireIgnoreJustification only allows ignore comments to suppress results if they give a justification, e.g.
+// tfsec:ignore:AWS002 -- logging is handled by the central bucket
+func OptionRequireIgnoreJustification() func(s *Scanner) {
+	return func(s *Scanner) {
+		s.requireIgnoreJustification = true
+	}
+}
+
+// OptionReportOnlyFiles only checks the blocks in the given files, and in any modules called from them, such as the files
+// changed by a pull request. The whole configuration is still parsed and evaluated, so values are resolved as usual.
+func OptionReportOnlyFiles(paths []string) func(s *Scanner) {
+	return func(s *Scanner) {
+		s.reportedFiles = make(map[string]bool)
+		for _, path := range paths {
+			s.reportedFiles[s.canonicalPath(path)] = true
+		}
+	}
+}
+
+// OptionIncludeTags only runs the rules which cover at least one of the given tags, e.g. encryption
+func OptionIncludeTags(tags []rule.Tag) func(s *Scanner) {
+	return func(s *Scanner) {
+		s.includedTags = tags
+	}
+}
+
+// OptionCompliance only runs the rules which are mapped to a control of the given compliance framework
+func OptionCompliance(framework rule.Framework) func(s *Scanner) {
+	return func(s *Scanner) {
+		s.compliance = framework
+	}
+}
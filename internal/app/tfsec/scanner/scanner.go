@@ -1,10 +1,12 @@
 package scanner
 
 import (
-	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/severity"
 
 	"github.com/tfsec/tfsec/pkg/result"
@@ -21,14 +23,28 @@ import (
 
 // Scanner scans HCL blocks by running all registered rules against them
 type Scanner struct {
-	includePassed   bool
-	includeIgnored  bool
-	excludedRuleIDs []string
+	includePassed     bool
+	includeIgnored    bool
+	includeUnresolved bool
+	excludedRuleIDs   []string
+	// requireIgnoreJustification means ignore comments only suppress results if they give a reason for doing so
+	requireIgnoreJustification bool
+	scannedFiles               map[string]bool         // files containing the blocks scanned so far
+	matchedIgnores             map[ignoreLocation]bool // ignore comments which have matched a result so far
+	reportedFiles              map[string]bool         // if set, only blocks in these files, or called from them, are checked
+	canonicalPaths             map[string]string
+	includedTags               []rule.Tag     // if set, only rules with one of these tags are run
+	compliance                 rule.Framework // if set, only rules mapped to this framework are run
+	diagnostics                diagnostic.Diagnostics
 }
 
 // New creates a new Scanner
 func New(options ...Option) *Scanner {
-	s := &Scanner{}
+	s := &Scanner{
+		scannedFiles:   make(map[string]bool),
+		matchedIgnores: make(map[ignoreLocation]bool),
+		canonicalPaths: make(map[string]string),
+	}
 	for _, option := range options {
 		option(s)
 	}
@@ -56,41 +72,95 @@ func (scanner *Scanner) Scan(blocks []*block.Block) []result.Result {
 	defer checkTime.Stop()
 	var results []result.Result
 	context := hclcontext.New(blocks)
-	rules := GetRegisteredRules()
+	rules := scanner.selectRules(GetRegisteredRules())
 	for _, checkBlock := range blocks {
+		if !scanner.isReported(checkBlock) {
+			continue
+		}
+		if filename := checkBlock.Range().Filename; filename != "" {
+			scanner.scannedFiles[filename] = true
+		}
 		for _, r := range rules {
 			func(r *rule.Rule) {
 				if rule.IsRuleRequiredForBlock(r, checkBlock) {
 					debug.Log("Running rule for %s on %s.%s (%s)...", r.ID, checkBlock.Type(), checkBlock.FullName(), checkBlock.Range().Filename)
-					ruleResults := rule.CheckRule(r, checkBlock, context)
+					ruleResults, err := rule.CheckRuleWithError(r, checkBlock, context)
+					if err != nil {
+						scanner.diagnostics = append(scanner.diagnostics, diagnostic.New(diagnostic.Evaluation, severity.Error, err.Error(), checkBlock.Range()))
+					}
 					if scanner.includePassed && ruleResults == nil {
 						res := result.New().WithRange(checkBlock.Range()).WithStatus(result.Passed).WithSeverity(severity.None)
 						results = append(results, *res)
 					} else if ruleResults != nil {
 						for _, ruleResult := range ruleResults.All() {
-							if scanner.includeIgnored || (!scanner.checkRangeIgnored(ruleResult.RuleID, ruleResult.Range, checkBlock.Range()) && !checkInList(ruleResult.RuleID, scanner.excludedRuleIDs)) {
+							// ignores are looked for first, so that an ignore of an unresolved check is not reported as unused
+							ign, ignored := scanner.findIgnore(ruleResult.RuleID, ruleResult.Range, checkBlock.Range())
+							if ruleResult.Status == result.Unresolved && !scanner.includeUnresolved {
+								debug.Log("Skipping unresolved '%s' result at %s", ruleResult.RuleID, ruleResult.Range)
+								continue
+							}
+							excluded := checkInList(ruleResult.RuleID, scanner.excludedRuleIDs)
+							if !ignored && !excluded {
 								results = append(results, ruleResult)
-							} else {
-								// rule was ignored
-								metrics.Add(metrics.IgnoredChecks, 1)
+								continue
+							}
+							// rule was ignored
+							metrics.Add(metrics.IgnoredChecks, 1)
+							if ignored {
 								debug.Log("Ignoring '%s' based on tfsec:ignore statement", ruleResult.RuleID)
+								ruleResult.WithIgnore(ign.justification, ign.expiryString())
+							} else {
+								debug.Log("Ignoring '%s' as the check is excluded", ruleResult.RuleID)
+								ruleResult.WithExclusion()
+							}
+							if scanner.includeIgnored {
+								results = append(results, ruleResult)
 							}
 						}
 					}
 				}
 			}(&r)
 		}
+		// attributes which could not be evaluated while checking are reported, as the checks may have missed problems
+		scanner.diagnostics = append(scanner.diagnostics, diagnostic.FromEvaluationErrors(checkBlock.EvaluationErrors().Take())...)
 	}
 	return results
 }
 
-func (scanner *Scanner) checkRangeIgnored(id string, r block.Range, b block.Range) bool {
+// Diagnostics returns the problems found while scanning, such as checks which failed or attributes which could not be
+// evaluated. A scanner can be used for several scans, in which case the problems found in all of them are returned.
+func (scanner *Scanner) Diagnostics() diagnostic.Diagnostics {
+	return scanner.diagnostics
+}
+
+// selectRules returns the rules to run, which are those with an included tag and a control of the chosen compliance
+// framework, if either has been given
+func (scanner *Scanner) selectRules(rules []rule.Rule) []rule.Rule {
+	if len(scanner.includedTags) == 0 && scanner.compliance == "" {
+		return rules
+	}
+	var selected []rule.Rule
+	for _, r := range rules {
+		if len(scanner.includedTags) > 0 && !r.HasTag(scanner.includedTags...) {
+			continue
+		}
+		if scanner.compliance != "" && len(r.ControlsFor(scanner.compliance)) == 0 {
+			continue
+		}
+		selected = append(selected, r)
+	}
+	debug.Log("Running %d of %d rules", len(selected), len(rules))
+	return selected
+}
+
+// findIgnore returns the ignore comment which suppresses a result, if there is one. Ignores are read from the lines of
+// the result and the line above them, and from the line above the block. Ignores which have expired are not used, nor
+// are ignores without a justification if one is required.
+func (scanner *Scanner) findIgnore(id string, r block.Range, b block.Range) (ignore, bool) {
 	raw, err := ioutil.ReadFile(r.Filename)
 	if err != nil {
-		return false
+		return ignore{}, false
 	}
-	ignoreAll := "tfsec:ignore:*"
-	ignoreCode := fmt.Sprintf("tfsec:ignore:%s", id)
 	lines := append([]string{""}, strings.Split(string(raw), "\n")...)
 	startLine := r.StartLine
 
@@ -99,35 +169,74 @@ func (scanner *Scanner) checkRangeIgnored(id string, r block.Range, b block.Rang
 		startLine = r.StartLine - 1
 	}
 
-	// check the line itself
+	var numbers []int
 	for number := startLine; number <= r.EndLine; number++ {
+		numbers = append(numbers, number)
+	}
+	// check the line above the block
+	numbers = append(numbers, b.StartLine-1)
+
+	// every matching ignore is recorded, even once one has been found, so that none are reported as unused
+	var found *ignore
+	now := time.Now()
+	for _, number := range numbers {
 		if number <= 0 || number >= len(lines) {
 			continue
 		}
-
-		if strings.Contains(lines[number], ignoreAll) || strings.Contains(lines[number], ignoreCode) {
-			return true
+		for _, ign := range parseIgnores(lines[number]) {
+			if !ign.matches(id) {
+				continue
+			}
+			scanner.matchedIgnores[ignoreLocation{r.Filename, number, ign.ruleID}] = true
+			if found != nil {
+				continue
+			}
+			if ign.expired(now) {
+				debug.Log("Ignore for '%s' at %s:%d expired on %s", id, r.Filename, number, ign.expiryString())
+				continue
+			}
+			if scanner.requireIgnoreJustification && ign.justification == "" {
+				debug.Log("Ignore for '%s' at %s:%d has no justification, which is required", id, r.Filename, number)
+				continue
+			}
+			ign := ign
+			found = &ign
 		}
 	}
+	if found == nil {
+		return ignore{}, false
+	}
+	return *found, true
+}
 
-	// check the line above the block
-	if b.StartLine-1 > 0 {
-		line := lines[b.StartLine-1]
-		if ignored := checkLineForIgnore(line, ignoreAll, ignoreCode); ignored {
+// isReported returns true if results should be reported for the block, which is the case for every block unless only
+// some files are reported. A block in a module is reported if it is in one of those files, or if any of the module
+// blocks which led to it are.
+func (scanner *Scanner) isReported(b *block.Block) bool {
+	if scanner.reportedFiles == nil {
+		return true
+	}
+	for current := b; current != nil; current = current.ModuleBlock() {
+		if filename := current.Range().Filename; filename != "" && scanner.reportedFiles[scanner.canonicalPath(filename)] {
 			return true
 		}
 	}
-
 	return false
 }
 
-func checkLineForIgnore(line, ignoreAll, ignoreCode string) bool {
-	line = strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(line, "//", ""), "#", ""))
-	segments := strings.Split(line, " ")
-	for _, segment := range segments {
-		if segment == ignoreAll || segment == ignoreCode {
-			return true
-		}
+// canonicalPath returns the absolute path of a file with any symlinks resolved, so that paths to the same file can be
+// compared however they were found
+func (scanner *Scanner) canonicalPath(path string) string {
+	if canonical, exists := scanner.canonicalPaths[path]; exists {
+		return canonical
 	}
-	return false
+	canonical := path
+	if abs, err := filepath.Abs(path); err == nil {
+		canonical = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(canonical); err == nil {
+		canonical = resolved
+	}
+	scanner.canonicalPaths[path] = canonical
+	return canonical
 }
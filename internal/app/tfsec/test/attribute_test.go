@@ -697,3 +697,77 @@ resource "boolean_something" "my-something" {
 		})
 	}
 }
+
+func Test_AttributeEvaluate(t *testing.T) {
+	var tests = []struct {
+		name               string
+		source             string
+		expectedState      block.ValueState
+		expectedUnresolved []string
+	}{
+		{
+			name: "literal value is known",
+			source: `
+resource "aws_s3_bucket" "my-bucket" {
+	acl = "private"
+}`,
+			expectedState: block.ValueKnown,
+		},
+		{
+			name: "value from a variable with a default is known",
+			source: `
+variable "acl" {
+	default = "private"
+}
+
+resource "aws_s3_bucket" "my-bucket" {
+	acl = var.acl
+}`,
+			expectedState: block.ValueKnown,
+		},
+		{
+			name: "value from an unset variable is unknown",
+			source: `
+variable "acl" {}
+
+resource "aws_s3_bucket" "my-bucket" {
+	acl = "${var.acl}-read"
+}`,
+			expectedState:      block.ValueUnknown,
+			expectedUnresolved: []string{"var.acl"},
+		},
+		{
+			name: "value from a data source is unknown",
+			source: `
+data "aws_ssm_parameter" "acl" {
+	name = "acl"
+}
+
+resource "aws_s3_bucket" "my-bucket" {
+	acl = data.aws_ssm_parameter.acl.value
+}`,
+			expectedState:      block.ValueUnknown,
+			expectedUnresolved: []string{"data.aws_ssm_parameter.acl.value"},
+		},
+		{
+			name: "failing function call is an error",
+			source: `
+resource "aws_s3_bucket" "my-bucket" {
+	acl = cidrsubnet("private", 1, 1)
+}`,
+			expectedState: block.ValueError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blocks := createBlocksFromSource(test.source)
+			for _, b := range block.Blocks(blocks).OfType("resource") {
+				evaluation := b.GetAttribute("acl").Evaluate()
+				assert.Equal(t, test.expectedState, evaluation.State)
+				assert.Equal(t, test.expectedUnresolved, evaluation.Unresolved)
+				assert.Equal(t, test.expectedState == block.ValueError, evaluation.Err != nil)
+			}
+		})
+	}
+}
@@ -72,6 +72,45 @@ func Test_AWSOpenIngressSecurityGroup(t *testing.T) {
 					`,
 			mustIncludeResultCode: rules.AWSOpenIngressSecurityGroupInlineRule,
 		},
+		{
+			name: "check dynamic blocks using the iterator value",
+			source: `
+		variable "ingress_cidrs" {
+			default = ["10.0.0.0/16", "0.0.0.0/0"]
+		}
+
+		resource "aws_security_group" "my-group" {
+			dynamic "ingress" {
+				for_each = var.ingress_cidrs
+				content {
+					cidr_blocks = [ingress.value]
+				}
+			}
+		}`,
+			mustIncludeResultCode: rules.AWSOpenIngressSecurityGroupInlineRule,
+		},
+		{
+			name: "check dynamic blocks using a custom iterator with private cidrs",
+			source: `
+		variable "rules" {
+			default = {
+				https = "10.0.0.0/16"
+				ssh   = "10.1.0.0/16"
+			}
+		}
+
+		resource "aws_security_group" "my-group" {
+			dynamic "ingress" {
+				for_each = var.rules
+				iterator = rule
+				content {
+					description = rule.key
+					cidr_blocks = [rule.value]
+				}
+			}
+		}`,
+			mustExcludeResultCode: rules.AWSOpenIngressSecurityGroupInlineRule,
+		},
 		{
 			name: "check aws_security_group multiple ingress on 0.0.0.0/0",
 			source: `
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/baseline"
+	"github.com/tfsec/tfsec/pkg/result"
+)
+
+func Test_BaselinedResultsSurviveLineChanges(t *testing.T) {
+
+	original := scanSource(`
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`)
+	require.Len(t, original, 1)
+	assert.Equal(t, "cidr_blocks", original[0].AttributePath)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "baseline.json")
+	require.NoError(t, baseline.New(original).Write(path))
+
+	accepted, err := baseline.Load(path)
+	require.NoError(t, err)
+	require.Len(t, accepted.Entries, 1)
+	assert.Equal(t, "AWS006", accepted.Entries[0].RuleID)
+	assert.Equal(t, "aws_security_group_rule.my-rule", accepted.Entries[0].Address)
+
+	// the existing finding has moved down the file, and a new one has been added
+	changed := scanSource(`
+resource "aws_security_group_rule" "new-rule" {
+    type        = "ingress"
+    description = "new ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+
+
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`)
+	statuses := make(map[string]result.Status)
+	for _, res := range accepted.Apply(changed) {
+		statuses[res.Address] = res.Status
+	}
+	assert.Equal(t, map[string]result.Status{
+		"aws_security_group_rule.my-rule":  result.Baselined,
+		"aws_security_group_rule.new-rule": result.Failed,
+	}, statuses)
+}
+
+func Test_AttributePathOfNestedBlocks(t *testing.T) {
+
+	blocks := createBlocksFromSource(`
+resource "aws_security_group" "my-group" {
+	description = "my group"
+	ingress {
+		cidr_blocks = ["10.0.0.0/16"]
+	}
+	ingress {
+		description = "public"
+		cidr_blocks = ["0.0.0.0/0"]
+	}
+}
+`)
+	require.Len(t, blocks, 1)
+	group := blocks[0]
+	assert.Equal(t, "description", group.AttributePath(group.GetAttribute("description").Range()))
+	ingress := group.GetBlocks("ingress")[1]
+	assert.Equal(t, "ingress[1].cidr_blocks", group.AttributePath(ingress.GetAttribute("cidr_blocks").Range()))
+	assert.Equal(t, "ingress[1]", group.AttributePath(ingress.Range()))
+	assert.Equal(t, "", group.AttributePath(group.Range()))
+}
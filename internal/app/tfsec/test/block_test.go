@@ -1,9 +1,11 @@
 package test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_IsPresentCheckOnBlock(t *testing.T) {
@@ -130,3 +132,25 @@ resource "aws_s3_bucket" "my-bucket" {
 		})
 	}
 }
+
+func Test_DynamicBlocksBindIterator(t *testing.T) {
+	blocks := createBlocksFromSource(`
+resource "aws_security_group" "my-group" {
+	dynamic "ingress" {
+		for_each = ["10.0.0.0/16", "10.1.0.0/16"]
+		iterator = rule
+		content {
+			description = "rule ${rule.key}"
+			cidr_blocks = [rule.value]
+		}
+	}
+}`)
+	require.Len(t, blocks, 1)
+	ingress := blocks[0].GetBlocks("ingress")
+	require.Len(t, ingress, 2)
+	for i, cidr := range []string{"10.0.0.0/16", "10.1.0.0/16"} {
+		assert.Equal(t, "ingress", ingress[i].Type())
+		assert.Equal(t, fmt.Sprintf("rule %d", i), ingress[i].GetAttribute("description").Value().AsString())
+		assert.True(t, ingress[i].GetAttribute("cidr_blocks").Contains(cidr))
+	}
+}
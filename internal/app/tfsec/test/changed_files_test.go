@@ -0,0 +1,114 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/gitdiff"
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+)
+
+func Test_OnlyChangedFilesAreReported(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	rule := func(name string) string {
+		return `
+resource "aws_security_group_rule" "` + name + `" {
+    type        = "ingress"
+    description = "` + name + ` ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`
+	}
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	rootDir := filepath.Join(dir, "main")
+	moduleDir := filepath.Join(dir, "module")
+	require.NoError(t, os.MkdirAll(rootDir, 0755))
+	require.NoError(t, os.MkdirAll(moduleDir, 0755))
+	writeFile := func(path string, contents string) {
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644))
+	}
+	runGitCommand := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=tfsec", "-c", "user.email=tfsec@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	writeFile(filepath.Join(rootDir, "main.tf"), `
+module "rules" {
+	source = "../module"
+}
+`)
+	writeFile(filepath.Join(rootDir, "existing.tf"), rule("existing"))
+	writeFile(filepath.Join(moduleDir, "main.tf"), rule("module"))
+	runGitCommand("init", "-q")
+	runGitCommand("add", "-A")
+	runGitCommand("commit", "-q", "-m", "initial")
+	runGitCommand("branch", "base")
+
+	reportedRules := func() []string {
+		changedFiles, err := gitdiff.ChangedFiles(rootDir, "base")
+		require.NoError(t, err)
+		blocks, err := parser.New(rootDir, parser.OptionStopOnHCLError()).ParseDirectory()
+		require.NoError(t, err)
+		results := scanner.New(
+			scanner.OptionExcludeRules(excludedChecksList),
+			scanner.OptionReportOnlyFiles(changedFiles),
+		).Scan(blocks)
+		var addresses []string
+		for _, res := range results {
+			addresses = append(addresses, res.Address)
+		}
+		sort.Strings(addresses)
+		return addresses
+	}
+
+	assert.Empty(t, reportedRules())
+
+	// an untracked file is reported
+	writeFile(filepath.Join(rootDir, "added.tf"), rule("added"))
+	assert.Equal(t, []string{"aws_security_group_rule.added"}, reportedRules())
+
+	// a committed change is reported, and a changed module call reports the blocks in the module
+	writeFile(filepath.Join(rootDir, "main.tf"), `
+module "rules" {
+	source = "../module"
+	# changed
+}
+`)
+	runGitCommand("add", "-A")
+	runGitCommand("commit", "-q", "-m", "changed")
+	assert.Equal(t, []string{
+		"aws_security_group_rule.added",
+		"module.rules.aws_security_group_rule.module",
+	}, reportedRules())
+}
+
+func Test_ChangedFilesRequiresGit(t *testing.T) {
+
+	emptyDir, err := ioutil.TempDir(os.TempDir(), "tfsec")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(emptyDir) }()
+
+	path := os.Getenv("PATH")
+	require.NoError(t, os.Setenv("PATH", emptyDir))
+	defer func() { _ = os.Setenv("PATH", path) }()
+
+	_, err = gitdiff.ChangedFiles(emptyDir, "main")
+	assert.EqualError(t, err, "git is required to find the files changed since 'main', but it could not be found on the PATH")
+}
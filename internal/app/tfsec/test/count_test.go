@@ -0,0 +1,34 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourcesWithCountOfZeroAreNotScanned(t *testing.T) {
+	results := scanSource(`
+resource "problem" "x" {
+	count = 0
+	bad = "1"
+}
+`)
+	assert.Len(t, results, 0)
+}
+
+func Test_CountedResourcesAreScannedPerInstance(t *testing.T) {
+	results := scanSource(`
+resource "problem" "x" {
+	count = 2
+	bad = "1"
+}
+`)
+	require.Len(t, results, 2)
+	var addresses []string
+	for _, res := range results {
+		assert.Equal(t, exampleCheckCode, res.RuleID)
+		addresses = append(addresses, res.Address)
+	}
+	assert.ElementsMatch(t, []string{"problem.x[0]", "problem.x[1]"}, addresses)
+}
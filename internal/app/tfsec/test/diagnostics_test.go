@@ -0,0 +1,122 @@
+package test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/formatters"
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+	"github.com/tfsec/tfsec/pkg/severity"
+)
+
+func Test_ProblemsReadingConfigurationAreReportedAsDiagnostics(t *testing.T) {
+	path := createTestFile("main.tf", `
+module "missing" {
+	source = "./does-not-exist"
+}
+
+resource "aws_s3_bucket" "my-bucket" {
+	bucket = "my-bucket"
+}
+`)
+	brokenPath := filepath.Join(filepath.Dir(path), "broken.tf")
+	require.NoError(t, ioutil.WriteFile(brokenPath, []byte(`resource "aws_s3_bucket" "broken" {`), 0600))
+
+	tfParser := parser.New(filepath.Dir(path))
+	blocks, err := tfParser.ParseDirectory()
+	require.NoError(t, err)
+	assert.NotEmpty(t, blocks)
+
+	found := make(map[diagnostic.Phase]diagnostic.Diagnostic)
+	for _, diag := range tfParser.Diagnostics() {
+		found[diag.Phase] = diag
+	}
+
+	require.Contains(t, found, diagnostic.Parse)
+	assert.Equal(t, severity.Error, found[diagnostic.Parse].Severity)
+	assert.Equal(t, brokenPath, found[diagnostic.Parse].Range.Filename)
+
+	require.Contains(t, found, diagnostic.ModuleLoad)
+	assert.Equal(t, severity.Warning, found[diagnostic.ModuleLoad].Severity)
+	assert.Equal(t, path, found[diagnostic.ModuleLoad].Range.Filename)
+	assert.Equal(t, 2, found[diagnostic.ModuleLoad].Range.StartLine)
+
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, formatters.FormatJSON(buffer, nil, tfParser.Diagnostics(), filepath.Dir(path)))
+	assert.Contains(t, buffer.String(), `"diagnostics"`)
+	assert.Contains(t, buffer.String(), `"phase": "module"`)
+
+	buffer.Reset()
+	require.NoError(t, formatters.FormatSarif(buffer, nil, tfParser.Diagnostics(), filepath.Dir(path)))
+	assert.Contains(t, buffer.String(), `"toolExecutionNotifications"`)
+	assert.Contains(t, buffer.String(), `"broken.tf"`)
+}
+
+// panickingExpression is an expression which panics when it is evaluated
+type panickingExpression struct {
+	hclsyntax.Expression
+	rng hcl.Range
+}
+
+func (e panickingExpression) Value(*hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	panic("evaluation failed")
+}
+
+func (e panickingExpression) Range() hcl.Range {
+	return e.rng
+}
+
+// createPanickingBlock creates a resource block with a bucket attribute which panics when it is evaluated
+func createPanickingBlock(resourceType string, evalErrors *block.EvaluationErrors) *block.Block {
+	rng := hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 3}, End: hcl.Pos{Line: 3}}
+	b := block.New(&hcl.Block{
+		Type:   "resource",
+		Labels: []string{resourceType, "example"},
+		Body: &hclsyntax.Body{
+			Attributes: hclsyntax.Attributes{
+				"bucket": &hclsyntax.Attribute{
+					Name:     "bucket",
+					Expr:     panickingExpression{rng: rng},
+					SrcRange: rng,
+				},
+			},
+		},
+		DefRange: rng,
+	}, nil, nil)
+	b.AttachEvaluationErrors(evalErrors)
+	return b
+}
+
+func Test_AttributeEvaluationPanicsAreReportedAsDiagnostics(t *testing.T) {
+
+	projectErrors := &block.EvaluationErrors{}
+	otherProjectErrors := &block.EvaluationErrors{}
+
+	attr := createPanickingBlock("aws_s3_bucket", projectErrors).GetAttribute("bucket")
+	assert.Equal(t, cty.NilVal, attr.Value())
+	evaluation := attr.Evaluate()
+	assert.Equal(t, block.ValueError, evaluation.State)
+	assert.EqualError(t, evaluation.Err, "failed to evaluate bucket: evaluation failed")
+
+	// the panics are only reported for the project the attribute belongs to, and only once
+	assert.Empty(t, otherProjectErrors.Take())
+	diagnostics := diagnostic.FromEvaluationErrors(projectErrors.Take())
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, diagnostic.Evaluation, diagnostics[0].Phase)
+	assert.Equal(t, severity.Error, diagnostics[0].Severity)
+	assert.Equal(t, "main.tf", diagnostics[0].Range.Filename)
+	assert.Equal(t, 3, diagnostics[0].Range.StartLine)
+	assert.Contains(t, diagnostics[0].Message, "failed to evaluate bucket: evaluation failed")
+	assert.Empty(t, projectErrors.Take())
+}
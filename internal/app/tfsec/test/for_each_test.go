@@ -0,0 +1,37 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/rules"
+)
+
+func Test_ForEachResourcesAreScannedPerInstance(t *testing.T) {
+	results := scanSource(`
+variable "buckets" {
+	default = {
+		private = "private"
+		public  = "public-read"
+	}
+}
+
+resource "aws_s3_bucket" "bucket" {
+	for_each = var.buckets
+	acl = each.value
+	logging {
+		target_bucket = "logs"
+	}
+}
+`)
+	var found []string
+	for _, res := range results {
+		if res.RuleID == rules.AWSBadBucketACL {
+			found = append(found, res.Address)
+		}
+	}
+	require.Len(t, found, 1)
+	assert.Equal(t, `aws_s3_bucket.bucket["public"]`, found[0])
+}
@@ -1,6 +1,8 @@
 package test
 
 import (
+	"bytes"
+	"path/filepath"
 	"testing"
 
 	"github.com/tfsec/tfsec/pkg/result"
@@ -9,6 +11,7 @@ import (
 	"github.com/tfsec/tfsec/internal/app/tfsec/hclcontext"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/formatters"
 
 	"github.com/tfsec/tfsec/pkg/rule"
 
@@ -94,3 +97,223 @@ resource "bad" "my-bad" {} //tfsec:ignore:ABC123
 	assert.Equal(t, results[0].RuleID, "DEF456")
 
 }
+
+func Test_IgnoreWithExpiry(t *testing.T) {
+
+	var tests = []struct {
+		name           string
+		source         string
+		expectedResult bool
+	}{
+		{
+			name: "ignore is used until its expiry date",
+			source: `
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    cidr_blocks = ["0.0.0.0/0"] # tfsec:ignore:AWS006:exp:2999-12-31
+}
+`,
+		},
+		{
+			name: "ignore is not used after its expiry date",
+			source: `
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    cidr_blocks = ["0.0.0.0/0"] # tfsec:ignore:AWS006:exp:2000-01-01
+}
+`,
+			expectedResult: true,
+		},
+		{
+			name: "ignore with an invalid expiry date is not used",
+			source: `
+# tfsec:ignore:AWS006:exp:tomorrow
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`,
+			expectedResult: true,
+		},
+		{
+			name: "ignore with an expiry date in a block comment is used",
+			source: `
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    cidr_blocks = ["0.0.0.0/0"] /* tfsec:ignore:AWS006:exp:2999-12-31*/
+}
+`,
+		},
+		{
+			name: "ignore with an expired date in a block comment is not used",
+			source: `
+/* tfsec:ignore:AWS006:exp:2000-01-01*/
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`,
+			expectedResult: true,
+		},
+		{
+			name: "ignore with an invalid expiry date in a block comment is not used",
+			source: `
+/* tfsec:ignore:AWS006:exp:2999-12-31abc*/
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`,
+			expectedResult: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results := scanSource(test.source)
+			if test.expectedResult {
+				assertCheckCode(t, "AWS006", "", results)
+			} else {
+				assertCheckCode(t, "", "AWS006", results)
+			}
+		})
+	}
+}
+
+func Test_IgnoredResultsKeepJustificationAndExpiry(t *testing.T) {
+
+	blocks := createBlocksFromSource(`
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    # tfsec:ignore:AWS006:exp:2999-12-31 -- the load balancer is public by design
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`)
+	results := scanner.New(scanner.OptionIncludeIgnored()).Scan(blocks)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "AWS006", results[0].RuleID)
+	assert.Equal(t, result.Ignored, results[0].Status)
+	assert.Equal(t, "the load balancer is public by design", results[0].IgnoreJustification)
+	assert.Equal(t, "2999-12-31", results[0].IgnoreExpiry)
+}
+
+func Test_IgnoreJustificationCanBeRequired(t *testing.T) {
+
+	blocks := createBlocksFromSource(`
+resource "aws_security_group_rule" "justified" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    cidr_blocks = ["0.0.0.0/0"] # tfsec:ignore:AWS006 -- the load balancer is public by design
+}
+
+resource "aws_security_group_rule" "unjustified" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    cidr_blocks = ["0.0.0.0/0"] # tfsec:ignore:AWS006
+}
+`)
+
+	assert.Len(t, scanner.New().Scan(blocks), 0)
+
+	results := scanner.New(scanner.OptionRequireIgnoreJustification()).Scan(blocks)
+	require.Len(t, results, 1)
+	assert.Equal(t, "AWS006", results[0].RuleID)
+	assert.Equal(t, 11, results[0].Range.StartLine)
+}
+
+func Test_UnusedIgnoresAreReported(t *testing.T) {
+
+	blocks := createBlocksFromSource(`
+# tfsec:ignore:AWS002
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    cidr_blocks = ["0.0.0.0/0"] # tfsec:ignore:AWS006 tfsec:ignore:XYZ999
+}
+`)
+	s := scanner.New()
+	assert.Len(t, s.Scan(blocks), 0)
+
+	unused := s.UnusedIgnores()
+	require.Len(t, unused, 2)
+
+	assert.Equal(t, "AWS002", unused[0].RuleID)
+	assert.Equal(t, 2, unused[0].Range.StartLine)
+	assert.False(t, unused[0].UnknownRule)
+
+	assert.Equal(t, "XYZ999", unused[1].RuleID)
+	assert.Equal(t, 6, unused[1].Range.StartLine)
+	assert.True(t, unused[1].UnknownRule)
+}
+
+func Test_IgnoresForDeselectedRulesAreNotReportedAsUnused(t *testing.T) {
+
+	const source = `
+# tfsec:ignore:AWS002
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    cidr_blocks = ["0.0.0.0/0"] # tfsec:ignore:AWS006 tfsec:ignore:XYZ999
+}
+`
+
+	var tests = []struct {
+		name           string
+		option         scanner.Option
+		expectedUnused []string
+	}{
+		{
+			name:           "rules without an included tag are not run",
+			option:         scanner.OptionIncludeTags([]rule.Tag{rule.TagLogging}),
+			expectedUnused: []string{"AWS002", "XYZ999"},
+		},
+		{
+			name:           "rules not mapped to the compliance framework are not run",
+			option:         scanner.OptionCompliance(rule.CISAWS14),
+			expectedUnused: []string{"XYZ999"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := scanner.New(test.option)
+			assert.Len(t, s.Scan(createBlocksFromSource(source)), 0)
+
+			var unused []string
+			for _, ign := range s.UnusedIgnores() {
+				unused = append(unused, ign.RuleID)
+			}
+			assert.Equal(t, test.expectedUnused, unused)
+		})
+	}
+}
+
+func Test_ExcludedResultsAreNotTreatedAsIgnoredByComments(t *testing.T) {
+
+	blocks := createBlocksFromSource(`
+resource "aws_security_group_rule" "my-rule" {
+    type        = "ingress"
+    description = "load balancer ingress"
+    cidr_blocks = ["0.0.0.0/0"]
+}
+`)
+	results := scanner.New(scanner.OptionIncludeIgnored(), scanner.OptionExcludeRules([]string{"AWS006"})).Scan(blocks)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "AWS006", results[0].RuleID)
+	assert.Equal(t, result.Ignored, results[0].Status)
+	assert.True(t, results[0].Excluded)
+	assert.Empty(t, results[0].IgnoreJustification)
+
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, formatters.FormatSarif(buffer, results, nil, filepath.Dir(results[0].Range.Filename)))
+	assert.Contains(t, buffer.String(), `"kind": "external"`)
+	assert.NotContains(t, buffer.String(), `"kind": "inSource"`)
+
+	buffer.Reset()
+	require.NoError(t, formatters.FormatJUnit(buffer, results, nil, ""))
+	assert.Contains(t, buffer.String(), "check excluded from the scan")
+	assert.NotContains(t, buffer.String(), "no justification given")
+}
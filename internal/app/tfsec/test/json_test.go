@@ -0,0 +1,86 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+	"github.com/tfsec/tfsec/internal/app/tfsec/rules"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+)
+
+func Test_JSONConfiguration(t *testing.T) {
+
+	var tests = []struct {
+		name                  string
+		source                string
+		mustIncludeResultCode string
+		mustExcludeResultCode string
+	}{
+		{
+			name: "check bucket without logging block",
+			source: `{
+	"resource": {
+		"aws_s3_bucket": {
+			"my-bucket": {
+				"bucket": "bucket"
+			}
+		}
+	}
+}`,
+			mustIncludeResultCode: rules.AWSNoBucketLogging,
+		},
+		{
+			name: "check bucket with logging block",
+			source: `{
+	"resource": {
+		"aws_s3_bucket": {
+			"my-bucket": {
+				"logging": {
+					"target_bucket": "target-bucket"
+				}
+			}
+		}
+	}
+}`,
+			mustExcludeResultCode: rules.AWSNoBucketLogging,
+		},
+		{
+			name: "check dynamic ingress block on 0.0.0.0/0",
+			source: `{
+	"variable": {
+		"cidrs": {
+			"default": ["0.0.0.0/0"]
+		}
+	},
+	"resource": {
+		"aws_security_group": {
+			"my-group": {
+				"dynamic": {
+					"ingress": {
+						"for_each": "${var.cidrs}",
+						"content": {
+							"cidr_blocks": ["${ingress.value}"]
+						}
+					}
+				}
+			}
+		}
+	}
+}`,
+			mustIncludeResultCode: rules.AWSOpenIngressSecurityGroupInlineRule,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := createTestFile("main.tf.json", test.source)
+			blocks, err := parser.New(filepath.Dir(path), parser.OptionStopOnHCLError()).ParseDirectory()
+			if err != nil {
+				t.Fatal(err)
+			}
+			results := scanner.New(scanner.OptionExcludeRules(excludedChecksList)).Scan(blocks)
+			assertCheckCode(t, test.mustIncludeResultCode, test.mustExcludeResultCode, results)
+		})
+	}
+}
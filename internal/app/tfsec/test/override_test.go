@@ -0,0 +1,40 @@
+package test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+	"github.com/tfsec/tfsec/internal/app/tfsec/rules"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+)
+
+func Test_OverrideFileEnablesEncryption(t *testing.T) {
+	path := createTestFile("main.tf", `
+resource "aws_s3_bucket" "my-bucket" {
+	bucket = "my-bucket"
+}
+`)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(filepath.Dir(path), "prod_override.tf"), []byte(`
+resource "aws_s3_bucket" "my-bucket" {
+	server_side_encryption_configuration {
+		rule {
+			apply_server_side_encryption_by_default {
+				sse_algorithm = "AES256"
+			}
+		}
+	}
+}
+`), 0600))
+
+	blocks, err := parser.New(filepath.Dir(path), parser.OptionStopOnHCLError()).ParseDirectory()
+	require.NoError(t, err)
+	assert.Len(t, blocks, 1)
+
+	results := scanner.New(scanner.OptionExcludeRules(excludedChecksList)).Scan(blocks)
+	assertCheckCode(t, "", rules.AWSUnencryptedS3Bucket, results)
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+	"github.com/tfsec/tfsec/internal/app/tfsec/rules"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+)
+
+func Test_PlanValuesAreScanned(t *testing.T) {
+	path := createTestFile("main.tf", `
+variable "acl" {}
+
+resource "aws_s3_bucket" "my-bucket" {
+	bucket = "my-bucket"
+	acl = var.acl
+}
+`)
+	planPath := filepath.Join(filepath.Dir(path), "plan.json")
+	require.NoError(t, ioutil.WriteFile(planPath, []byte(`{
+	"format_version": "0.2",
+	"planned_values": {
+		"root_module": {
+			"resources": [
+				{
+					"address": "aws_s3_bucket.my-bucket",
+					"mode": "managed",
+					"type": "aws_s3_bucket",
+					"name": "my-bucket",
+					"values": {"bucket": "my-bucket", "acl": "public-read"}
+				}
+			]
+		}
+	},
+	"configuration": {
+		"root_module": {}
+	}
+}`), 0600))
+
+	blocks, err := parser.New(filepath.Dir(path)).ParsePlan(planPath)
+	require.NoError(t, err)
+
+	results := scanner.New(scanner.OptionExcludeRules(excludedChecksList)).Scan(blocks)
+	assertCheckCode(t, rules.AWSBadBucketACL, "", results)
+
+	for _, res := range results {
+		if res.RuleID == rules.AWSBadBucketACL {
+			assert.Equal(t, path, res.Range.Filename)
+			assert.Equal(t, 6, res.Range.StartLine)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/hclcontext"
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+)
+
+func Test_ProviderConfigIsResolvedThroughModules(t *testing.T) {
+
+	const rootProviders = `
+provider "aws" {
+	region = "us-east-1"
+}
+
+provider "aws" {
+	alias = "eu"
+	region = "eu-west-1"
+}
+`
+
+	var tests = []struct {
+		name           string
+		source         string
+		moduleSource   string
+		expectedRegion string
+	}{
+		{
+			name: "default provider is inherited by a module",
+			source: rootProviders + `
+module "bucket" {
+	source = "../module"
+}
+`,
+			moduleSource: `
+resource "aws_s3_bucket" "bucket" {}
+`,
+			expectedRegion: "us-east-1",
+		},
+		{
+			name: "aliased provider is passed as the default provider of a module",
+			source: rootProviders + `
+module "bucket" {
+	source = "../module"
+	providers = {
+		aws = aws.eu
+	}
+}
+`,
+			moduleSource: `
+resource "aws_s3_bucket" "bucket" {}
+`,
+			expectedRegion: "eu-west-1",
+		},
+		{
+			name: "aliased provider is passed as an aliased provider of a module",
+			source: rootProviders + `
+module "bucket" {
+	source = "../module"
+	providers = {
+		aws = aws
+		aws.replica = aws.eu
+	}
+}
+`,
+			moduleSource: `
+resource "aws_s3_bucket" "bucket" {
+	provider = aws.replica
+}
+`,
+			expectedRegion: "eu-west-1",
+		},
+		{
+			name: "provider defined in a module is used by its resources",
+			source: rootProviders + `
+module "bucket" {
+	source = "../module"
+}
+`,
+			moduleSource: `
+provider "aws" {
+	region = "ap-southeast-2"
+}
+
+resource "aws_s3_bucket" "bucket" {}
+`,
+			expectedRegion: "ap-southeast-2",
+		},
+		{
+			name: "aliased provider is not inherited without being passed",
+			source: rootProviders + `
+module "bucket" {
+	source = "../module"
+}
+`,
+			moduleSource: `
+resource "aws_s3_bucket" "bucket" {
+	provider = aws.eu
+}
+`,
+		},
+		{
+			name: "default provider is not inherited when providers are passed explicitly",
+			source: rootProviders + `
+module "bucket" {
+	source = "../module"
+	providers = {
+		aws.replica = aws.eu
+	}
+}
+`,
+			moduleSource: `
+resource "aws_s3_bucket" "bucket" {}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := createTestFileWithModule(test.source, test.moduleSource)
+			blocks, err := parser.New(path, parser.OptionStopOnHCLError()).ParseDirectory()
+			require.NoError(t, err)
+
+			buckets := block.Blocks(blocks).OfType("resource")
+			require.Len(t, buckets, 1)
+			provider := hclcontext.New(blocks).GetProviderConfig(buckets[0])
+			if test.expectedRegion == "" {
+				assert.Nil(t, provider)
+				return
+			}
+			require.NotNil(t, provider)
+			assert.True(t, provider.GetAttribute("region").Equals(test.expectedRegion))
+		})
+	}
+}
+
+func Test_ProviderConfigIsResolvedThroughAlias(t *testing.T) {
+
+	blocks := createBlocksFromSource(`
+provider "aws" {
+	region = "us-east-1"
+}
+
+provider "aws" {
+	alias = "eu"
+	region = "eu-west-1"
+}
+
+resource "aws_s3_bucket" "default" {}
+
+resource "aws_s3_bucket" "eu" {
+	provider = aws.eu
+}
+
+resource "google_storage_bucket" "unconfigured" {}
+`)
+
+	ctx := hclcontext.New(blocks)
+	regions := make(map[string]string)
+	for _, resource := range block.Blocks(blocks).OfType("resource") {
+		if provider := ctx.GetProviderConfig(resource); provider != nil {
+			regions[resource.FullName()] = provider.GetAttribute("region").Value().AsString()
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"aws_s3_bucket.default": "us-east-1",
+		"aws_s3_bucket.eu":      "eu-west-1",
+	}, regions)
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/hclcontext"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+	"github.com/tfsec/tfsec/pkg/result"
+	"github.com/tfsec/tfsec/pkg/rule"
+	"github.com/tfsec/tfsec/pkg/severity"
+)
+
+func Test_RulesAreSelectedByTagsAndCompliance(t *testing.T) {
+
+	const source = `
+resource "aws_s3_bucket" "my-bucket" {
+}
+`
+
+	var tests = []struct {
+		name            string
+		options         []scanner.Option
+		expectedRuleIDs []string
+	}{
+		{
+			name:            "every rule is run by default",
+			expectedRuleIDs: []string{"AWS002", "AWS017", "AWS077"},
+		},
+		{
+			name:            "only rules with an included tag are run",
+			options:         []scanner.Option{scanner.OptionIncludeTags([]rule.Tag{rule.TagLogging})},
+			expectedRuleIDs: []string{"AWS002"},
+		},
+		{
+			name:            "rules with any of the included tags are run",
+			options:         []scanner.Option{scanner.OptionIncludeTags([]rule.Tag{rule.TagLogging, rule.TagEncryption})},
+			expectedRuleIDs: []string{"AWS002", "AWS017"},
+		},
+		{
+			name:            "only rules mapped to the compliance framework are run",
+			options:         []scanner.Option{scanner.OptionCompliance(rule.CISAWS14)},
+			expectedRuleIDs: []string{"AWS017"},
+		},
+		{
+			name: "tags and compliance framework are both applied",
+			options: []scanner.Option{
+				scanner.OptionIncludeTags([]rule.Tag{rule.TagLogging}),
+				scanner.OptionCompliance(rule.PCIDSS321),
+			},
+			expectedRuleIDs: []string{"AWS002"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := append([]scanner.Option{scanner.OptionExcludeRules(excludedChecksList)}, test.options...)
+			results := scanner.New(options...).Scan(createBlocksFromSource(source))
+			ruleIDs := make(map[string]bool)
+			for _, res := range results {
+				ruleIDs[res.RuleID] = true
+			}
+			var found []string
+			for id := range ruleIDs {
+				found = append(found, id)
+			}
+			sort.Strings(found)
+			assert.Equal(t, test.expectedRuleIDs, found)
+		})
+	}
+}
+
+func Test_ResultsWithoutSeverityHaveDefaultSeverity(t *testing.T) {
+
+	scanner.RegisterCheckRule(rule.Rule{
+		ID: "SEV001",
+		Documentation: rule.RuleDocumentation{
+			Summary: "Check with a default severity",
+		},
+		DefaultSeverity: severity.Warning,
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"default_severity"},
+		CheckFunc: func(set result.Set, b *block.Block, _ *hclcontext.Context) {
+			set.Add(result.New().WithDescription("no severity").WithRange(b.Range()))
+			set.Add(result.New().WithDescription("own severity").WithRange(b.Range()).WithSeverity(severity.Error))
+		},
+	})
+
+	results := scanSource(`
+resource "default_severity" "example" {
+}
+`)
+	severities := make(map[string]severity.Severity)
+	for _, res := range results {
+		if res.RuleID == "SEV001" {
+			severities[res.Description] = res.Severity
+		}
+	}
+	require.Len(t, severities, 2)
+	assert.Equal(t, severity.Warning, severities["no severity"])
+	assert.Equal(t, severity.Error, severities["own severity"])
+}
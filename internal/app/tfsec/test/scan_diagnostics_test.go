@@ -0,0 +1,79 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/internal/app/tfsec/hclcontext"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+	"github.com/tfsec/tfsec/pkg/diagnostic"
+	"github.com/tfsec/tfsec/pkg/result"
+	"github.com/tfsec/tfsec/pkg/rule"
+	"github.com/tfsec/tfsec/pkg/severity"
+)
+
+func Test_PanickingChecksAreReportedAsDiagnostics(t *testing.T) {
+
+	scanner.RegisterCheckRule(rule.Rule{
+		ID: "PAN001",
+		Documentation: rule.RuleDocumentation{
+			Summary: "Check which panics",
+		},
+		DefaultSeverity: severity.Error,
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"panicking_check"},
+		CheckFunc: func(set result.Set, b *block.Block, _ *hclcontext.Context) {
+			panic("check failed")
+		},
+	})
+
+	projectScanner := scanner.New()
+	projectScanner.Scan(createBlocksFromSource(`
+resource "panicking_check" "example" {
+}
+`))
+
+	var messages []string
+	for _, diag := range projectScanner.Diagnostics() {
+		assert.Equal(t, diagnostic.Evaluation, diag.Phase)
+		assert.Equal(t, 2, diag.Range.StartLine)
+		messages = append(messages, diag.Message)
+	}
+	assert.Equal(t, []string{"check PAN001 was skipped due to error(s): check failed"}, messages)
+}
+
+func Test_AttributeEvaluationPanicsAreReportedByTheScannerOfTheirProject(t *testing.T) {
+
+	scanner.RegisterCheckRule(rule.Rule{
+		ID: "PAN002",
+		Documentation: rule.RuleDocumentation{
+			Summary: "Check of an attribute which panics",
+		},
+		DefaultSeverity: severity.Error,
+		RequiredTypes:   []string{"resource"},
+		RequiredLabels:  []string{"panicking_attribute"},
+		CheckFunc: func(set result.Set, b *block.Block, _ *hclcontext.Context) {
+			_ = b.GetAttribute("bucket").Value()
+		},
+	})
+
+	projectScanner := scanner.New()
+	projectScanner.Scan([]*block.Block{createPanickingBlock("panicking_attribute", &block.EvaluationErrors{})})
+	otherProjectScanner := scanner.New()
+	otherProjectScanner.Scan(createBlocksFromSource(`
+resource "panicking_attribute" "example" {
+	bucket = "example"
+}
+`))
+
+	var messages []string
+	for _, diag := range projectScanner.Diagnostics() {
+		assert.Equal(t, diagnostic.Evaluation, diag.Phase)
+		assert.Equal(t, 3, diag.Range.StartLine)
+		messages = append(messages, diag.Message)
+	}
+	assert.Equal(t, []string{"failed to evaluate bucket: evaluation failed"}, messages)
+	assert.Empty(t, otherProjectScanner.Diagnostics())
+}
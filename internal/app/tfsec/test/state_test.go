@@ -0,0 +1,61 @@
+package test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/formatters"
+	"github.com/tfsec/tfsec/internal/app/tfsec/parser"
+	"github.com/tfsec/tfsec/internal/app/tfsec/rules"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+	"github.com/tfsec/tfsec/pkg/result"
+)
+
+func Test_StateResourcesAreScanned(t *testing.T) {
+	path := createTestFile("terraform.tfstate", `{
+	"version": 4,
+	"resources": [
+		{
+			"module": "module.storage",
+			"mode": "managed",
+			"type": "aws_s3_bucket",
+			"name": "imported",
+			"instances": [
+				{"attributes": {"bucket": "imported", "acl": "public-read"}}
+			]
+		}
+	]
+}`)
+
+	blocks, err := parser.LoadState(path)
+	require.NoError(t, err)
+
+	results := scanner.New(scanner.OptionExcludeRules(excludedChecksList)).Scan(blocks)
+	assertCheckCode(t, rules.AWSBadBucketACL, "", results)
+
+	var found []result.Result
+	for _, res := range results {
+		if res.RuleID == rules.AWSBadBucketACL {
+			found = append(found, res)
+		}
+	}
+	require.Len(t, found, 1)
+	assert.Equal(t, "module.storage.aws_s3_bucket.imported", found[0].Address)
+	assert.Equal(t, "", found[0].Range.Filename)
+
+	for name, formatter := range map[string]formatters.Formatter{
+		"json":       formatters.FormatJSON,
+		"csv":        formatters.FormatCSV,
+		"checkstyle": formatters.FormatCheckStyle,
+		"junit":      formatters.FormatJUnit,
+		"sarif":      formatters.FormatSarif,
+	} {
+		buffer := bytes.NewBuffer(nil)
+		require.NoError(t, formatter(buffer, found, nil, filepath.Dir(path)), name)
+		assert.Contains(t, buffer.String(), "module.storage.aws_s3_bucket.imported", name)
+	}
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/formatters"
+	"github.com/tfsec/tfsec/internal/app/tfsec/rules"
+	"github.com/tfsec/tfsec/internal/app/tfsec/scanner"
+	"github.com/tfsec/tfsec/pkg/result"
+)
+
+func Test_UnresolvedValuesAreOnlyReportedWhenRequested(t *testing.T) {
+	blocks := createBlocksFromSource(`
+variable "acl" {}
+
+resource "aws_s3_bucket" "my-bucket" {
+	acl = var.acl
+}
+`)
+
+	results := scanner.New(scanner.OptionExcludeRules(excludedChecksList)).Scan(blocks)
+	assertCheckCode(t, "", rules.AWSBadBucketACL, results)
+
+	results = scanner.New(scanner.OptionExcludeRules(excludedChecksList), scanner.OptionIncludeUnresolved()).Scan(blocks)
+	var found []result.Result
+	for _, res := range results {
+		if res.RuleID == rules.AWSBadBucketACL {
+			found = append(found, res)
+		}
+	}
+	require.Len(t, found, 1)
+	assert.Equal(t, result.Unresolved, found[0].Status)
+	assert.Equal(t, []string{"var.acl"}, found[0].Unresolved)
+	assert.Equal(t, 5, found[0].Range.StartLine)
+}
+
+func Test_UnresolvedResultsAreFormatted(t *testing.T) {
+	res := result.New().
+		WithRuleID(rules.AWSBadBucketACL).
+		WithDescription("Resource 'aws_s3_bucket.my-bucket' could not be checked, as the value of 'acl' is not known.").
+		WithAddress("aws_s3_bucket.my-bucket").
+		WithRoot("stacks/storage").
+		WithStatus(result.Unresolved)
+	res.Unresolved = []string{"var.acl"}
+
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, formatters.FormatSarif(buffer, []result.Result{*res}, nil, ""))
+	assert.Contains(t, buffer.String(), `"kind": "open"`)
+	assert.Contains(t, buffer.String(), `"root": "stacks/storage"`)
+	assert.Contains(t, buffer.String(), `"var.acl"`)
+
+	buffer.Reset()
+	require.NoError(t, formatters.FormatJUnit(buffer, []result.Result{*res}, nil, ""))
+	assert.Contains(t, buffer.String(), `failures="0"`)
+	assert.Contains(t, buffer.String(), `<skipped message=`)
+}
@@ -0,0 +1,89 @@
+package diagnostic
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/pkg/severity"
+)
+
+// Phase is the stage of a scan in which a diagnostic was raised
+type Phase string
+
+const (
+	Parse      Phase = "parse"
+	ModuleLoad Phase = "module"
+	Evaluation Phase = "evaluation"
+)
+
+// Diagnostic is a problem found while reading the configuration, rather than a problem with the configuration itself.
+// Diagnostics mean some of the configuration may not have been scanned, or not with fully evaluated values.
+type Diagnostic struct {
+	Severity severity.Severity `json:"severity"`
+	Phase    Phase             `json:"phase"`
+	Message  string            `json:"message"`
+	Range    block.Range       `json:"location"`
+}
+
+type Diagnostics []Diagnostic
+
+func New(phase Phase, sev severity.Severity, message string, codeRange block.Range) Diagnostic {
+	return Diagnostic{
+		Severity: sev,
+		Phase:    phase,
+		Message:  message,
+		Range:    codeRange,
+	}
+}
+
+// FromError creates a diagnostic for an error, which is converted from hcl diagnostics if it is one
+func FromError(phase Phase, err error) Diagnostics {
+	if diags, ok := err.(hcl.Diagnostics); ok {
+		return FromHCL(phase, diags)
+	}
+	return Diagnostics{New(phase, severity.Error, err.Error(), block.Range{})}
+}
+
+// FromHCL converts diagnostics reported by hcl
+func FromHCL(phase Phase, diags hcl.Diagnostics) Diagnostics {
+	var diagnostics Diagnostics
+	for _, diag := range diags {
+		sev := severity.Error
+		if diag.Severity == hcl.DiagWarning {
+			sev = severity.Warning
+		}
+		message := diag.Summary
+		if diag.Detail != "" {
+			message = fmt.Sprintf("%s; %s", diag.Summary, diag.Detail)
+		}
+		var codeRange block.Range
+		if diag.Subject != nil {
+			codeRange = block.Range{
+				Filename:  diag.Subject.Filename,
+				StartLine: diag.Subject.Start.Line,
+				EndLine:   diag.Subject.End.Line,
+			}
+		}
+		diagnostics = append(diagnostics, New(phase, sev, message, codeRange))
+	}
+	return diagnostics
+}
+
+// FromEvaluationErrors converts the panics recovered while evaluating attributes
+func FromEvaluationErrors(errs []block.EvaluationError) Diagnostics {
+	var diagnostics Diagnostics
+	for _, err := range errs {
+		diagnostics = append(diagnostics, New(Evaluation, severity.Error, err.Err.Error(), err.Range))
+	}
+	return diagnostics
+}
+
+// String describes the diagnostic in a single line
+func (d Diagnostic) String() string {
+	if d.Range.Filename == "" {
+		return fmt.Sprintf("%s: %s", d.Phase, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Range, d.Phase, d.Message)
+}
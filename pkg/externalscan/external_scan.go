@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/tfsec/tfsec/pkg/diagnostic"
 	"github.com/tfsec/tfsec/pkg/result"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/block"
@@ -18,6 +19,7 @@ import (
 type ExternalScanner struct {
 	paths           []string
 	internalOptions []scanner.Option
+	diagnostics     diagnostic.Diagnostics
 }
 
 func NewExternalScanner(options ...Option) *ExternalScanner {
@@ -46,12 +48,15 @@ func (t *ExternalScanner) Scan() ([]result.Result, error) {
 		return nil, err
 	}
 
+	t.diagnostics = nil
 	for _, dir := range dirs {
-		blocks, err := parser.New(dir).ParseDirectory()
+		dirParser := parser.New(dir)
+		blocks, err := dirParser.ParseDirectory()
 		if err != nil {
 			return nil, err
 		}
 		projectBlocks[dir] = blocks
+		t.diagnostics = append(t.diagnostics, dirParser.Diagnostics()...)
 	}
 
 	var results []result.Result
@@ -64,6 +69,12 @@ func (t *ExternalScanner) Scan() ([]result.Result, error) {
 	return results, nil
 }
 
+// Diagnostics returns any problems found while reading the configuration during the last scan, which mean that the
+// results may be incomplete
+func (t *ExternalScanner) Diagnostics() diagnostic.Diagnostics {
+	return t.diagnostics
+}
+
 func findTFRootModules(paths []string) ([]string, error) {
 
 	var output []string
@@ -1,7 +1,10 @@
 package result
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -20,10 +23,19 @@ type Result struct {
 	Resolution      string            `json:"resolution"`
 	Links           []string          `json:"links"`
 	Range           block.Range       `json:"location"`
+	Address         string            `json:"address,omitempty"`
+	Root            string            `json:"root,omitempty"`
 	Description     string            `json:"description"`
 	RangeAnnotation string            `json:"-"`
 	Severity        severity.Severity `json:"severity"`
 	Status          Status            `json:"status"`
+	Unresolved      []string          `json:"unresolved_references,omitempty"`
+	AttributePath   string            `json:"attribute_path,omitempty"`
+	// the reason and expiry date given by the ignore comment which suppressed the result, if any
+	IgnoreJustification string `json:"ignore_justification,omitempty"`
+	IgnoreExpiry        string `json:"ignore_expiry,omitempty"`
+	// Excluded means the result was ignored because its check was excluded from the scan, rather than by a comment
+	Excluded bool `json:"excluded,omitempty"`
 }
 
 type Status string
@@ -32,6 +44,10 @@ const (
 	Failed  Status = "failed"
 	Passed  Status = "passed"
 	Ignored Status = "ignored"
+	// Unresolved results are checks which could not be made, as a value they depend on is not known
+	Unresolved Status = "unresolved"
+	// Baselined results are failed checks which were accepted when a baseline was written, so only new failures are reported
+	Baselined Status = "baselined"
 )
 
 func New() *Result {
@@ -45,7 +61,19 @@ func (r *Result) Passed() bool {
 }
 
 func (r *Result) HashCode() string {
-	return fmt.Sprintf("%s:%s", r.Range, r.RuleID)
+	if r.Root != "" {
+		// the same module can be called from several root modules, and is evaluated separately for each of them
+		return fmt.Sprintf("%s:%s:%s:%s", r.Root, r.Range, r.Address, r.RuleID)
+	}
+	return fmt.Sprintf("%s:%s:%s", r.Range, r.Address, r.RuleID)
+}
+
+// Fingerprint identifies the result by its rule, the address of its resource and the path to the attribute it was raised
+// for, rather than by line numbers, so that it stays the same as code around it changes. Baselines are keyed by it.
+func (r *Result) Fingerprint() string {
+	key := strings.Join([]string{r.Root, r.RuleID, r.Address, r.AttributePath}, "|")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
 }
 
 func (r *Result) WithRuleID(id string) *Result {
@@ -88,6 +116,23 @@ func (r *Result) WithRange(codeRange block.Range) *Result {
 	return r
 }
 
+func (r *Result) WithAddress(address string) *Result {
+	r.Address = address
+	return r
+}
+
+// WithAttributePath records the path of the attribute which caused the result, relative to the block
+func (r *Result) WithAttributePath(path string) *Result {
+	r.AttributePath = path
+	return r
+}
+
+// WithRoot records the root module a result was found in, when several root modules are scanned
+func (r *Result) WithRoot(root string) *Result {
+	r.Root = root
+	return r
+}
+
 func (r *Result) WithDescription(description string) *Result {
 	r.Description = description
 	return r
@@ -103,6 +148,30 @@ func (r *Result) WithStatus(status Status) *Result {
 	return r
 }
 
+// WithIgnore marks the result as suppressed by an ignore comment, recording the justification and expiry date it gave
+func (r *Result) WithIgnore(justification string, expiry string) *Result {
+	r.Status = Ignored
+	r.IgnoreJustification = justification
+	r.IgnoreExpiry = expiry
+	return r
+}
+
+// WithExclusion marks the result as ignored because its check was excluded from the scan, e.g. with --exclude
+func (r *Result) WithExclusion() *Result {
+	r.Status = Ignored
+	r.Excluded = true
+	return r
+}
+
+// WithUnresolvedAttribute marks the result as a check which could not be made, because the value of the attribute is
+// not known, and records the references which could not be resolved
+func (r *Result) WithUnresolvedAttribute(attr *block.Attribute) *Result {
+	r.Status = Unresolved
+	r.Range = attr.Range()
+	r.Unresolved = attr.Evaluate().Unresolved
+	return r
+}
+
 func (r *Result) WithAttributeAnnotation(attr *block.Attribute) *Result {
 
 	var raw interface{}
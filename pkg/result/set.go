@@ -1,6 +1,10 @@
 package result
 
-import "github.com/tfsec/tfsec/pkg/provider"
+import (
+	"github.com/tfsec/tfsec/internal/app/tfsec/block"
+	"github.com/tfsec/tfsec/pkg/provider"
+	"github.com/tfsec/tfsec/pkg/severity"
+)
 
 type Set interface {
 	Add(result *Result)
@@ -10,6 +14,9 @@ type Set interface {
 	WithImpact(impact string) Set
 	WithResolution(resolution string) Set
 	WithLinks(links []string) Set
+	WithDefaultSeverity(sev severity.Severity) Set
+	WithAddress(address string) Set
+	WithBlock(b *block.Block) Set
 	All() []Result
 }
 
@@ -18,13 +25,16 @@ func NewSet() *resultSet {
 }
 
 type resultSet struct {
-	results      []Result
-	ruleID       string
-	ruleSummary  string
-	ruleProvider provider.Provider
-	impact       string
-	resolution   string
-	links        []string
+	results         []Result
+	ruleID          string
+	ruleSummary     string
+	ruleProvider    provider.Provider
+	impact          string
+	resolution      string
+	links           []string
+	address         string
+	block           *block.Block
+	defaultSeverity severity.Severity
 }
 
 func (s *resultSet) Add(result *Result) {
@@ -33,7 +43,14 @@ func (s *resultSet) Add(result *Result) {
 		WithRuleSummary(s.ruleSummary).
 		WithImpact(s.impact).
 		WithResolution(s.resolution).
-		WithRuleProvider(s.ruleProvider)
+		WithRuleProvider(s.ruleProvider).
+		WithAddress(s.address)
+	if result.Severity == "" {
+		result.WithSeverity(s.defaultSeverity)
+	}
+	if s.block != nil {
+		result.WithAttributePath(s.block.AttributePath(result.Range))
+	}
 	s.results = append(s.results, *result)
 }
 
@@ -70,3 +87,22 @@ func (r *resultSet) WithLinks(links []string) Set {
 	r.links = links
 	return r
 }
+
+// WithDefaultSeverity sets the severity of results which are not given one of their own
+func (r *resultSet) WithDefaultSeverity(sev severity.Severity) Set {
+	r.defaultSeverity = sev
+	return r
+}
+
+func (r *resultSet) WithAddress(address string) Set {
+	r.address = address
+	return r
+}
+
+// WithBlock sets the block the results are raised for, which gives each result the address of the block, and the path
+// to the attribute it was raised for
+func (r *resultSet) WithBlock(b *block.Block) Set {
+	r.block = b
+	r.address = b.Address()
+	return r
+}
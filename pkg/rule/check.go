@@ -19,10 +19,21 @@ import (
 
 // CheckRule the provided HCL block against the rule
 func CheckRule(r *Rule, block *block.Block, ctx *hclcontext.Context) result.Set {
+	results, err := CheckRuleWithError(r, block, ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: %s\n", err)
+	}
+	return results
+}
+
+// CheckRuleWithError checks the provided HCL block against the rule. An error is returned if the rule panics, in which
+// case the block could not be checked.
+func CheckRuleWithError(r *Rule, block *block.Block, ctx *hclcontext.Context) (results result.Set, err error) {
 	defer func() {
-		if err := recover(); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "WARNING: skipped %s r due to error(s): %s\n", r.ID, err)
+		if recovered := recover(); recovered != nil {
 			debug.Log("Stack trace for failed %s r:\n%s\n\n", r.ID, string(runtimeDebug.Stack()))
+			results = nil
+			err = fmt.Errorf("check %s was skipped due to error(s): %v", r.ID, recovered)
 		}
 	}()
 
@@ -40,10 +51,12 @@ func CheckRule(r *Rule, block *block.Block, ctx *hclcontext.Context) result.Set
 		WithImpact(r.Documentation.Impact).
 		WithResolution(r.Documentation.Resolution).
 		WithRuleProvider(r.Provider).
-		WithLinks(links)
+		WithLinks(links).
+		WithDefaultSeverity(r.DefaultSeverity).
+		WithBlock(block)
 
 	r.CheckFunc(resultSet, block, ctx)
-	return resultSet
+	return resultSet, nil
 }
 
 // IsRuleRequiredForBlock returns true if the Rule should be applied to the given HCL block
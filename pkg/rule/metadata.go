@@ -0,0 +1,93 @@
+package rule
+
+// Tag is an area of security which a rule covers, so that a scan can be limited to the rules for it
+type Tag string
+
+const (
+	TagEncryption Tag = "encryption"
+	TagLogging    Tag = "logging"
+	TagNetwork    Tag = "network"
+	TagIAM        Tag = "iam"
+	TagBackup     Tag = "backup"
+	TagHardening  Tag = "hardening"
+)
+
+var ValidTags = []Tag{
+	TagEncryption, TagLogging, TagNetwork, TagIAM, TagBackup, TagHardening,
+}
+
+// Framework is a version of a compliance framework which rules can be mapped to
+type Framework string
+
+const (
+	CISAWS14   Framework = "cis-aws-1.4"
+	CISAzure13 Framework = "cis-azure-1.3"
+	CISGCP12   Framework = "cis-gcp-1.2"
+	PCIDSS321  Framework = "pci-dss-3.2.1"
+)
+
+var ValidFrameworks = []Framework{
+	CISAWS14, CISAzure13, CISGCP12, PCIDSS321,
+}
+
+var frameworkNames = map[Framework]string{
+	CISAWS14:   "CIS AWS Foundations Benchmark 1.4",
+	CISAzure13: "CIS Microsoft Azure Foundations Benchmark 1.3",
+	CISGCP12:   "CIS Google Cloud Platform Foundation Benchmark 1.2",
+	PCIDSS321:  "PCI DSS 3.2.1",
+}
+
+// Control is a control of a compliance framework, e.g. control 2.1.1 of the CIS AWS Foundations Benchmark
+type Control struct {
+	Framework Framework
+	ID        string
+}
+
+func (t Tag) IsValid() bool {
+	for _, tag := range ValidTags {
+		if tag == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Framework) IsValid() bool {
+	for _, framework := range ValidFrameworks {
+		if framework == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the full name of the framework, e.g. "PCI DSS 3.2.1"
+func (f Framework) Name() string {
+	if name, exists := frameworkNames[f]; exists {
+		return name
+	}
+	return string(f)
+}
+
+// HasTag returns true if the rule covers any of the given tags
+func (r *Rule) HasTag(tags ...Tag) bool {
+	for _, tag := range tags {
+		for _, ruleTag := range r.Tags {
+			if ruleTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ControlsFor returns the IDs of the controls of the framework which the rule is mapped to
+func (r *Rule) ControlsFor(framework Framework) []string {
+	var ids []string
+	for _, control := range r.Compliance {
+		if control.Framework == framework {
+			ids = append(ids, control.ID)
+		}
+	}
+	return ids
+}
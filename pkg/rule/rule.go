@@ -3,6 +3,7 @@ package rule
 import (
 	"github.com/tfsec/tfsec/pkg/provider"
 	"github.com/tfsec/tfsec/pkg/result"
+	"github.com/tfsec/tfsec/pkg/severity"
 
 	"github.com/tfsec/tfsec/internal/app/tfsec/block"
 	"github.com/tfsec/tfsec/internal/app/tfsec/hclcontext"
@@ -11,9 +12,15 @@ import (
 // Rule is a targeted security test which can be applied to terraform templates. It includes the types to run on e.g.
 // "resource", and the labels to run on e.g. "aws_s3_bucket".
 type Rule struct {
-	ID             string
-	Documentation  RuleDocumentation
-	Provider       provider.Provider
+	ID            string
+	Documentation RuleDocumentation
+	Provider      provider.Provider
+	// DefaultSeverity is the severity of the results of the rule, unless a result is given a severity of its own
+	DefaultSeverity severity.Severity
+	// Tags are the areas of security the rule covers, e.g. encryption
+	Tags []Tag
+	// Compliance lists the controls of compliance frameworks which the rule helps to meet
+	Compliance     []Control
 	RequiredTypes  []string
 	RequiredLabels []string
 	CheckFunc      func(result.Set, *block.Block, *hclcontext.Context)